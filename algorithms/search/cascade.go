@@ -0,0 +1,106 @@
+package search
+
+import "cmp"
+
+// Cascade finds a value in many sorted lists at once by fractional cascading,
+// a query takes O(log(n)+k) comparisons for k lists of n elements instead of
+// the O(klog(n)) of a binary search in each list.
+//
+// Each list is merged with every other element of the merged list below it,
+// so that the place of a value in a merged list tells its place in the list
+// and, within one element, in the merged list below.
+type Cascade[T any] struct {
+	compare func(a, b T) int
+	levels  []cascadeLevel[T]
+}
+
+// cascadeLevel is a list merged with half of the level below it, own[p] is the
+// number of elements of the list in merged[:p] and down[p] the number of those
+// from the level below.
+type cascadeLevel[T any] struct {
+	merged []T
+	own    []int
+	down   []int
+}
+
+// NewCascade returns a Cascade over lists, which are in increasing order and
+// must not change while it is used.
+func NewCascade[T cmp.Ordered](lists ...[]T) *Cascade[T] {
+	return NewCascadeFunc(cmp.Compare[T], lists...)
+}
+
+// NewCascadeFunc returns a Cascade over lists in the order of compare
+func NewCascadeFunc[T any](compare func(a, b T) int, lists ...[]T) *Cascade[T] {
+	c := &Cascade[T]{compare: compare, levels: make([]cascadeLevel[T], len(lists))}
+	for i := len(lists) - 1; i >= 0; i-- {
+		var promoted []T
+		if i+1 < len(lists) {
+			// the odd elements of the level below
+			below := c.levels[i+1].merged
+			for j := 1; j < len(below); j += 2 {
+				promoted = append(promoted, below[j])
+			}
+		}
+		list := lists[i]
+		l := cascadeLevel[T]{
+			merged: make([]T, 0, len(list)+len(promoted)),
+			own:    make([]int, 1, len(list)+len(promoted)+1),
+			down:   make([]int, 1, len(list)+len(promoted)+1),
+		}
+		a, b := 0, 0
+		for a < len(list) || b < len(promoted) {
+			if b == len(promoted) || a < len(list) && compare(list[a], promoted[b]) <= 0 {
+				l.merged = append(l.merged, list[a])
+				a++
+			} else {
+				l.merged = append(l.merged, promoted[b])
+				b++
+			}
+			l.own = append(l.own, a)
+			l.down = append(l.down, b)
+		}
+		c.levels[i] = l
+	}
+	return c
+}
+
+// LowerBounds returns LowerBound(lists[i], x) for each list in bounds,
+// which is reused if it has room for them.
+func (c *Cascade[T]) LowerBounds(x T, bounds []int) []int {
+	bounds = bounds[:0]
+	if len(c.levels) == 0 {
+		return bounds
+	}
+	p := LowerBoundFunc(c.levels[0].merged, x, c.compare)
+	for i, l := range c.levels {
+		bounds = append(bounds, l.own[p])
+		if i+1 == len(c.levels) {
+			break
+		}
+		// the promoted elements below merged[p] are the odd ones of the
+		// level below before 2*down[p], so the bound there is 2*down[p]
+		// unless the even one at it is less than x too
+		below := c.levels[i+1].merged
+		p = 2 * l.down[p]
+		if p < len(below) && c.compare(below[p], x) < 0 {
+			p++
+		}
+	}
+	return bounds
+}
+
+/*
+Complexity of fractional cascading：
+	* Build: 	O(n*k)
+	* Query: 	O(log(n) + k)
+	* Memory: 	O(n*k)
+	* Wiki: 	https://en.wikipedia.org/wiki/Fractional_cascading
+Shortcome from wiki:
+	Fractional cascading is a technique to speed up a sequence of binary
+	searches for the same value in a sequence of related data structures.
+	The first binary search takes logarithmic time, and each successive
+	search in the sequence takes constant time. Each list is augmented
+	with every other element of the augmented list after it, together with
+	pointers from each element into the lists, so that the position found
+	in one augmented list gives the position in the next.
+*/
@@ -0,0 +1,59 @@
+package search
+
+// Number is a type whose values interpolation search may interpolate
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Interpolation returns LowerBound(s, x) by interpolation search: it probes
+// s where x would be if the values of s grew linearly between its ends,
+// which takes O(log(log(n))) probes for uniformly distributed values. When
+// a probe fails to halve the part of s left it bisects instead, so that no
+// input takes more than O(log(n)) probes. s must not hold NaNs.
+func Interpolation[T Number](s []T, x T) int {
+	lo, hi := 0, len(s)
+	bisect := false
+	for lo < hi {
+		if s[lo] >= x {
+			return lo
+		}
+		if s[hi-1] < x {
+			return hi
+		}
+		// s[lo] < x <= s[hi-1], so the probe is in [lo, hi-1]
+		var probe int
+		if bisect {
+			probe = int(uint(lo+hi) >> 1)
+		} else {
+			f := (float64(x) - float64(s[lo])) / (float64(s[hi-1]) - float64(s[lo]))
+			probe = lo + int(f*float64(hi-1-lo))
+			probe = min(max(probe, lo), hi-1)
+		}
+		n := hi - lo
+		if s[probe] < x {
+			lo = probe + 1
+		} else {
+			hi = probe
+		}
+		bisect = !bisect && hi-lo > n/2
+	}
+	return lo
+}
+
+/*
+Complexity of interpolation search：
+	* Best: 	O(1)
+	* Average: 	O(log(log(n)))
+	* Worst: 	O(log(n))
+	* Memory: 	O(1)
+	* Wiki: 	https://en.wikipedia.org/wiki/Interpolation_search
+Shortcome from wiki:
+	Interpolation search is an algorithm for searching for a key in an
+	array that has been ordered by numerical values assigned to the keys.
+	On average the interpolation search makes about log(log(n)) comparisons
+	if the elements are uniformly distributed. In the worst case it can make
+	up to O(n) comparisons, which a search alternating interpolation and
+	bisection steps bounds to O(log(n)).
+*/
@@ -0,0 +1,127 @@
+/*
+Package search queries sorted slices, as sorted by algorithms/sort:
+
+  - LowerBound, UpperBound and EqualRange find where a value is or would go by binary search.
+  - Gallop finds the same place as LowerBound by exponential search, in O(log(i)) for an index i.
+  - Interpolation guesses it from the values, in O(log(log(n))) for uniformly distributed numbers.
+  - Cascade finds it in many sorted lists at once by fractional cascading.
+  - Merge, Union, Intersect, Difference and Dedup combine sorted slices as multisets.
+
+Each function takes a slice in increasing order of cmp.Compare, the XxxFunc
+form takes a slice in increasing order of compare.
+*/
+package search
+
+import "cmp"
+
+// LowerBound returns the first index of s whose element is not less than x,
+// len(s) if there is none. It is where x is in s, or where it would be inserted.
+func LowerBound[T cmp.Ordered](s []T, x T) int {
+	return LowerBoundFunc(s, x, cmp.Compare[T])
+}
+
+// LowerBoundFunc is LowerBound for a slice in the order of compare, compare
+// returns a negative number if the element is before the target x.
+func LowerBoundFunc[T, K any](s []T, x K, compare func(T, K) int) int {
+	lo, hi := 0, len(s)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if compare(s[mid], x) < 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// UpperBound returns the first index of s whose element is greater than x,
+// len(s) if there is none. It is where x would be inserted after its equals.
+func UpperBound[T cmp.Ordered](s []T, x T) int {
+	return UpperBoundFunc(s, x, cmp.Compare[T])
+}
+
+// UpperBoundFunc is UpperBound for a slice in the order of compare
+func UpperBoundFunc[T, K any](s []T, x K, compare func(T, K) int) int {
+	lo, hi := 0, len(s)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if compare(s[mid], x) <= 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// EqualRange returns the bounds of the elements of s equal to x, s[lo:hi]
+// holds them and is empty at the place of x if there are none.
+func EqualRange[T cmp.Ordered](s []T, x T) (lo, hi int) {
+	return EqualRangeFunc(s, x, cmp.Compare[T])
+}
+
+// EqualRangeFunc is EqualRange for a slice in the order of compare, it bisects
+// the whole slice once until it meets an equal element and then each side of it.
+func EqualRangeFunc[T, K any](s []T, x K, compare func(T, K) int) (lo, hi int) {
+	lo, hi = 0, len(s)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		switch c := compare(s[mid], x); {
+		case c < 0:
+			lo = mid + 1
+		case c > 0:
+			hi = mid
+		default:
+			return lo + LowerBoundFunc(s[lo:mid], x, compare), mid + 1 + UpperBoundFunc(s[mid+1:hi], x, compare)
+		}
+	}
+	return lo, lo
+}
+
+// Gallop returns LowerBound(s, x) by exponential search: it compares x with
+// the elements at 0, 2, 6, 14... until one is not less and then bisects the
+// last step, so an index i takes O(log(i)) comparisons instead of O(log(n)).
+// It suits searches whose answer is usually near the front of s, like the
+// merges of TimSort.
+func Gallop[T cmp.Ordered](s []T, x T) int {
+	return GallopFunc(s, x, cmp.Compare[T])
+}
+
+// GallopFunc is Gallop for a slice in the order of compare
+func GallopFunc[T, K any](s []T, x K, compare func(T, K) int) int {
+	// s[:lo] is less than x, the answer is at most hi
+	lo, step := 0, 1
+	hi := len(s)
+	for lo < len(s) {
+		i := lo + step - 1
+		if i >= len(s) {
+			break
+		}
+		if compare(s[i], x) >= 0 {
+			hi = i
+			break
+		}
+		lo = i + 1
+		step *= 2
+	}
+	return lo + LowerBoundFunc(s[lo:hi], x, compare)
+}
+
+/*
+Complexity of binary search：
+	* Best: 	O(1)
+	* Average: 	O(log(n))
+	* Worst: 	O(log(n))
+	* Memory: 	O(1)
+	* Wiki: 	https://en.wikipedia.org/wiki/Binary_search_algorithm
+Shortcome from wiki:
+	Binary search compares the target value to the middle element of the
+	array. If they are not equal, the half in which the target cannot lie is
+	eliminated and the search continues on the remaining half, again taking
+	the middle element to compare to the target value, and repeating this
+	until the target value is found. Exponential search extends binary
+	search to unbounded lists: it finds the first power of two greater than
+	the target's index and then binary searches below it, which takes
+	O(log(i)) where i is the index of the target.
+*/
@@ -0,0 +1,120 @@
+package search_test
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/man-fish/goalgorithms/algorithms/search"
+)
+
+// sortedInts returns n sorted ints with repeats in [0, max)
+func sortedInts(n, max int) []int {
+	s := make([]int, n)
+	for i := range s {
+		s[i] = rand.Intn(max)
+	}
+	slices.Sort(s)
+	return s
+}
+
+// the linear scans the searches are checked against
+func lowerBound(s []int, x int) int {
+	i := 0
+	for i < len(s) && s[i] < x {
+		i++
+	}
+	return i
+}
+
+func upperBound(s []int, x int) int {
+	i := 0
+	for i < len(s) && s[i] <= x {
+		i++
+	}
+	return i
+}
+
+func TestBounds(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 100, 1000} {
+		s := sortedInts(n, n/3+1)
+		for x := -1; x <= n/3+1; x++ {
+			lo, hi := lowerBound(s, x), upperBound(s, x)
+			if i := search.LowerBound(s, x); i != lo {
+				t.Fatalf("lower bound of %d in %v: wanted %d but get %d", x, s, lo, i)
+			}
+			if i := search.UpperBound(s, x); i != hi {
+				t.Fatalf("upper bound of %d in %v: wanted %d but get %d", x, s, hi, i)
+			}
+			if l, h := search.EqualRange(s, x); l != lo || h != hi {
+				t.Fatalf("range of %d in %v: wanted [%d, %d) but get [%d, %d)", x, s, lo, hi, l, h)
+			}
+			if i := search.Gallop(s, x); i != lo {
+				t.Fatalf("gallop to %d in %v: wanted %d but get %d", x, s, lo, i)
+			}
+			if i := search.Interpolation(s, x); i != lo {
+				t.Fatalf("interpolation of %d in %v: wanted %d but get %d", x, s, lo, i)
+			}
+		}
+	}
+}
+
+func TestBoundsFunc(t *testing.T) {
+	type user struct {
+		name string
+		age  int
+	}
+	users := []user{{"a", 20}, {"b", 31}, {"c", 31}, {"d", 45}}
+	byAge := func(u user, age int) int { return cmp.Compare(u.age, age) }
+	if lo, hi := search.EqualRangeFunc(users, 31, byAge); lo != 1 || hi != 3 {
+		t.Errorf("wanted [1, 3) but get [%d, %d)", lo, hi)
+	}
+	if i := search.GallopFunc(users, 40, byAge); i != 3 {
+		t.Errorf("wanted 3 but get %d", i)
+	}
+}
+
+func TestInterpolation(t *testing.T) {
+	// skewed values, which would take linear probes without bisection
+	s := make([]float64, 1<<12)
+	for i := range s {
+		s[i] = float64(i) * float64(i) * float64(i)
+	}
+	s[len(s)-1] = 1e300
+	for _, i := range []int{0, 1, 100, 2000, len(s) - 2, len(s) - 1} {
+		if got := search.Interpolation(s, s[i]); got != i {
+			t.Errorf("wanted %d but get %d", i, got)
+		}
+		// 1e300+0.5 rounds to 1e300
+		if got := search.Interpolation(s, s[i]+0.5); got != i+1 && s[i]+0.5 != s[i] {
+			t.Errorf("wanted %d but get %d", i+1, got)
+		}
+	}
+	u := []uint64{0, 1, 1 << 63, 1<<64 - 1}
+	if i := search.Interpolation(u, 1<<63); i != 2 {
+		t.Errorf("wanted 2 but get %d", i)
+	}
+}
+
+func TestCascade(t *testing.T) {
+	for _, k := range []int{0, 1, 2, 5} {
+		lists := make([][]int, k)
+		for i := range lists {
+			lists[i] = sortedInts(rand.Intn(200), 300)
+		}
+		c := search.NewCascade(lists...)
+		var bounds []int
+		for x := -1; x <= 301; x++ {
+			bounds = c.LowerBounds(x, bounds)
+			if len(bounds) != k {
+				t.Fatalf("wanted %d bounds but get %v", k, bounds)
+			}
+			for i, l := range lists {
+				if want := lowerBound(l, x); bounds[i] != want {
+					t.Fatalf("list %d of %d, %d: wanted %d but get %d", i, k, x, want, bounds[i])
+				}
+			}
+		}
+	}
+}
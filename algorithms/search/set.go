@@ -0,0 +1,130 @@
+package search
+
+import "cmp"
+
+// Merge returns the elements of a and b in order, those of a
+// go before the equal ones of b.
+func Merge[T cmp.Ordered](a, b []T) []T {
+	return MergeFunc(a, b, cmp.Compare[T])
+}
+
+// MergeFunc is Merge for slices in the order of compare
+func MergeFunc[T any](a, b []T, compare func(a, b T) int) []T {
+	dst := make([]T, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if compare(b[j], a[i]) < 0 {
+			dst = append(dst, b[j])
+			j++
+		} else {
+			dst = append(dst, a[i])
+			i++
+		}
+	}
+	dst = append(dst, a[i:]...)
+	return append(dst, b[j:]...)
+}
+
+// Union returns the elements of a or b in order as multisets: an element
+// which is m times in a and n times in b is max(m, n) times in the union,
+// the first min(m, n) of them from a.
+func Union[T cmp.Ordered](a, b []T) []T {
+	return UnionFunc(a, b, cmp.Compare[T])
+}
+
+// UnionFunc is Union for slices in the order of compare
+func UnionFunc[T any](a, b []T, compare func(a, b T) int) []T {
+	var dst []T
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch c := compare(a[i], b[j]); {
+		case c < 0:
+			dst = append(dst, a[i])
+			i++
+		case c > 0:
+			dst = append(dst, b[j])
+			j++
+		default:
+			dst = append(dst, a[i])
+			i++
+			j++
+		}
+	}
+	dst = append(dst, a[i:]...)
+	return append(dst, b[j:]...)
+}
+
+// Intersect returns the elements of a and b in order as multisets: an
+// element which is m times in a and n times in b is min(m, n) times in the
+// intersection, all of them from a.
+func Intersect[T cmp.Ordered](a, b []T) []T {
+	return IntersectFunc(a, b, cmp.Compare[T])
+}
+
+// IntersectFunc is Intersect for slices in the order of compare
+func IntersectFunc[T any](a, b []T, compare func(a, b T) int) []T {
+	var dst []T
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch c := compare(a[i], b[j]); {
+		case c < 0:
+			// skip the run of a before b[j] at once
+			i += GallopFunc(a[i:], b[j], compare)
+		case c > 0:
+			j += GallopFunc(b[j:], a[i], compare)
+		default:
+			dst = append(dst, a[i])
+			i++
+			j++
+		}
+	}
+	return dst
+}
+
+// Difference returns the elements of a not in b in order as multisets: an
+// element which is m times in a and n times in b is max(m-n, 0) times in the
+// difference, the last ones of a.
+func Difference[T cmp.Ordered](a, b []T) []T {
+	return DifferenceFunc(a, b, cmp.Compare[T])
+}
+
+// DifferenceFunc is Difference for slices in the order of compare
+func DifferenceFunc[T any](a, b []T, compare func(a, b T) int) []T {
+	var dst []T
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch c := compare(a[i], b[j]); {
+		case c < 0:
+			dst = append(dst, a[i])
+			i++
+		case c > 0:
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return append(dst, a[i:]...)
+}
+
+// Dedup removes the repeats of each element of s in place and returns the
+// shortened s, the first of equal elements is kept.
+func Dedup[T cmp.Ordered](s []T) []T {
+	return DedupFunc(s, cmp.Compare[T])
+}
+
+// DedupFunc is Dedup for a slice in the order of compare
+func DedupFunc[T any](s []T, compare func(a, b T) int) []T {
+	if len(s) < 2 {
+		return s
+	}
+	n := 1
+	for _, v := range s[1:] {
+		if compare(s[n-1], v) != 0 {
+			s[n] = v
+			n++
+		}
+	}
+	clear(s[n:])
+	return s[:n]
+}
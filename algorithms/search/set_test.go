@@ -0,0 +1,70 @@
+package search_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/man-fish/goalgorithms/algorithms/search"
+)
+
+// counts counts the elements of s, the multiset the set operations are checked with
+func counts(s []int) map[int]int {
+	m := make(map[int]int)
+	for _, v := range s {
+		m[v]++
+	}
+	return m
+}
+
+// fromCounts returns the sorted elements of m
+func fromCounts(m map[int]int) []int {
+	var s []int
+	for v, n := range m {
+		for ; n > 0; n-- {
+			s = append(s, v)
+		}
+	}
+	slices.Sort(s)
+	return s
+}
+
+func TestSetOperations(t *testing.T) {
+	for k := 0; k < 200; k++ {
+		a, b := sortedInts(rand.Intn(30), 15), sortedInts(rand.Intn(30), 15)
+		ca, cb := counts(a), counts(b)
+		union, inter, diff, merged := map[int]int{}, map[int]int{}, map[int]int{}, map[int]int{}
+		for v := 0; v < 15; v++ {
+			union[v] = max(ca[v], cb[v])
+			inter[v] = min(ca[v], cb[v])
+			diff[v] = max(ca[v]-cb[v], 0)
+			merged[v] = ca[v] + cb[v]
+		}
+		check := func(name string, got []int, want map[int]int) {
+			t.Helper()
+			if w := fromCounts(want); !slices.Equal(got, w) {
+				t.Fatalf("%s of %v and %v: wanted %v but get %v", name, a, b, w, got)
+			}
+		}
+		check("merge", search.Merge(a, b), merged)
+		check("union", search.Union(a, b), union)
+		check("intersect", search.Intersect(a, b), inter)
+		check("difference", search.Difference(a, b), diff)
+
+		want := slices.Compact(slices.Clone(a))
+		if got := search.Dedup(slices.Clone(a)); !slices.Equal(got, want) {
+			t.Fatalf("dedup of %v: wanted %v but get %v", a, want, got)
+		}
+	}
+}
+
+func TestMergeStable(t *testing.T) {
+	type tagged struct{ v, from int }
+	a := []tagged{{1, 0}, {2, 0}, {2, 0}}
+	b := []tagged{{0, 1}, {2, 1}, {3, 1}}
+	got := search.MergeFunc(a, b, func(x, y tagged) int { return x.v - y.v })
+	want := []tagged{{0, 1}, {1, 0}, {2, 0}, {2, 0}, {2, 1}, {3, 1}}
+	if !slices.Equal(got, want) {
+		t.Errorf("wanted %v but get %v", want, got)
+	}
+}
@@ -0,0 +1,105 @@
+package sort
+
+import (
+	"cmp"
+	"math/bits"
+)
+
+// BitonicSort is a O(nlog(n)^2) unstable sorting algorithm for a power of two
+// elements, it sorts the halves of data in opposite directions, which makes it
+// bitonic, and merges them comparing each element of the first half with the one
+// at the same place of the second. Below opts.Grain the halves are sorted with
+// PdqSort, above it the halves and the merges run on goroutines. Less and Swap
+// are called concurrently on distinct elements and must be safe for it.
+func BitonicSort(data Sortable, opts ParallelOptions) {
+	n := data.Len()
+	if n&(n-1) != 0 {
+		panic("sort: BitonicSort of a length which is not a power of two")
+	}
+	bitonicSort(newForker(opts), data, 0, n, true)
+}
+
+// BitonicSortSlice sorts s in the order of compare with BitonicSort
+func BitonicSortSlice[T any](s []T, compare func(a, b T) int, opts ParallelOptions) {
+	BitonicSort(&funcSlice[T]{s: s, compare: compare}, opts)
+}
+
+// BitonicSortOrdered sorts s in increasing order with BitonicSort
+func BitonicSortOrdered[T cmp.Ordered](s []T, opts ParallelOptions) {
+	BitonicSort(orderedSlice[T](s), opts)
+}
+
+// bitonicSort sorts the n elements of data from a, in
+// increasing order if up is set and decreasing otherwise.
+func bitonicSort(f *forker, data Sortable, a, n int, up bool) {
+	if n <= f.grain {
+		// pdqsort takes data[a-1] for the pivot of an enclosing partition
+		pdqsort(&window{data: data, a: a, n: n}, 0, n, bits.Len(uint(n)))
+		if !up {
+			reverseRange(data, a, a+n)
+		}
+		return
+	}
+	m := n / 2
+	f.fork(func() {
+		bitonicSort(f, data, a, m, true)
+	}, func() {
+		bitonicSort(f, data, a+m, m, false)
+	})
+	bitonicMerge(f, data, a, n, up)
+}
+
+// bitonicMerge sorts the bitonic n elements of data from a, each element of
+// the first half is compared with the one m after it so that both halves are
+// bitonic and none of the first is after any of the second.
+func bitonicMerge(f *forker, data Sortable, a, n int, up bool) {
+	if n < 2 {
+		return
+	}
+	m := n / 2
+	for i := a; i < a+m; i++ {
+		if up && data.Less(i+m, i) || !up && data.Less(i, i+m) {
+			data.Swap(i, i+m)
+		}
+	}
+	if n <= f.grain {
+		bitonicMerge(f, data, a, m, up)
+		bitonicMerge(f, data, a+m, m, up)
+		return
+	}
+	f.fork(func() {
+		bitonicMerge(f, data, a, m, up)
+	}, func() {
+		bitonicMerge(f, data, a+m, m, up)
+	})
+}
+
+// window is the n elements of data from a as a Sortable of its own
+type window struct {
+	data Sortable
+	a, n int
+}
+
+func (w *window) Len() int            { return w.n }
+func (w *window) Less(i, j int) bool  { return w.data.Less(w.a+i, w.a+j) }
+func (w *window) Equal(i, j int) bool { return w.data.Equal(w.a+i, w.a+j) }
+func (w *window) Swap(i, j int)       { w.data.Swap(w.a+i, w.a+j) }
+
+/*
+Complexity of bitonic sort：
+	* Best: 	O(nlog(n)^2)
+	* Average: 	O(nlog(n)^2)
+	* Worst: 	O(nlog(n)^2)
+	* Memory: 	O(log(n))
+	* Stable: 	No
+	* Wiki: 	https://en.wikipedia.org/wiki/Bitonic_sorter
+Shortcome from wiki:
+	Bitonic mergesort is a parallel algorithm for sorting. It is also used
+	as a construction method for building a sorting network. The algorithm
+	was devised by Ken Batcher. The resulting sorting networks consist of
+	O(nlog(n)^2) comparators and have a delay of O(log(n)^2), where n is
+	the number of items to be sorted. A sorted sequence is a monotonically
+	non-decreasing (or non-increasing) sequence. A bitonic sequence is a
+	sequence with x0 ≤ ... ≤ xk ≥ ... ≥ xn−1 for some k, or a circular
+	shift of such a sequence.
+*/
@@ -1,5 +1,7 @@
 package sort
 
+import "cmp"
+
 // BubbleSort is a O(n^2) stable sorting algorithm
 func BubbleSort(data Sortable) {
 	for i := 1; i < data.Len(); i++ {
@@ -11,6 +13,16 @@ func BubbleSort(data Sortable) {
 	}
 }
 
+// BubbleSortSlice sorts s in the order of compare with BubbleSort
+func BubbleSortSlice[T any](s []T, compare func(a, b T) int) {
+	BubbleSort(&funcSlice[T]{s: s, compare: compare})
+}
+
+// BubbleSortOrdered sorts s in increasing order with BubbleSort
+func BubbleSortOrdered[T cmp.Ordered](s []T) {
+	BubbleSort(orderedSlice[T](s))
+}
+
 /*
 Complexity of bubble sort：
 	* Best: 	O(n)
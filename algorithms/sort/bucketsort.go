@@ -0,0 +1,128 @@
+package sort
+
+import "math"
+
+// MaxBuckets bounds the buckets, holes or counts the distribution sorts
+// allocate for the range of their input, 1<<20 of them take 8MB or more.
+const MaxBuckets = 1 << 20
+
+// BucketOptions configures BucketSort
+type BucketOptions struct {
+	// Buckets is the number of buckets, len(s) if 0,
+	// it is never more than len(s) or MaxBuckets.
+	Buckets int
+	// Sort sorts each bucket, PdqSortOrdered if nil so that a bucket
+	// which holds much of the input does not take quadratic time.
+	Sort func(s []float64)
+}
+
+// BucketSort is a O(n) sorting algorithm for uniformly distributed
+// floats, it distributes s over buckets which split its range evenly and
+// sorts each of them. The NaNs go first like with slices.Sort, if the range
+// of s is infinite it sorts with PdqSort instead.
+func BucketSort(s []float64, opts BucketOptions) {
+	s = nanFirst(s)
+	lo, hi, ok := floatRange(s)
+	if !ok {
+		return
+	}
+	if math.IsInf(hi-lo, 0) {
+		PdqSortOrdered(s)
+		return
+	}
+	buckets := opts.Buckets
+	if buckets <= 0 || buckets > len(s) {
+		buckets = len(s)
+	}
+	buckets = min(buckets, MaxBuckets)
+	inner := opts.Sort
+	if inner == nil {
+		inner = PdqSortOrdered[float64]
+	}
+
+	// count[b+1] is the size of bucket b and then its start
+	count := make([]int, buckets+1)
+	for _, v := range s {
+		count[bucketOf(v, lo, hi, buckets)+1]++
+	}
+	for b := 1; b < len(count); b++ {
+		count[b] += count[b-1]
+	}
+	buf := make([]float64, len(s))
+	next := append([]int(nil), count[:buckets]...)
+	for _, v := range s {
+		b := bucketOf(v, lo, hi, buckets)
+		buf[next[b]] = v
+		next[b]++
+	}
+	copy(s, buf)
+	for b := 0; b < buckets; b++ {
+		if count[b+1]-count[b] > 1 {
+			inner(s[count[b]:count[b+1]])
+		}
+	}
+}
+
+// bucketOf returns the bucket of v when [lo, hi] is split in n
+func bucketOf(v, lo, hi float64, n int) int {
+	b := int(float64(n) * ((v - lo) / (hi - lo)))
+	if b >= n {
+		// v is hi, or rounded up to it
+		b = n - 1
+	}
+	return b
+}
+
+// nanFirst moves the NaNs of s to its front keeping the order of
+// the other elements, and returns the part of s after them.
+func nanFirst(s []float64) []float64 {
+	n := 0
+	for i := len(s) - 1; i >= 0; i-- {
+		if math.IsNaN(s[i]) {
+			continue
+		}
+		s[len(s)-1-n] = s[i]
+		n++
+	}
+	nans := len(s) - n
+	for i := 0; i < nans; i++ {
+		s[i] = math.NaN()
+	}
+	return s[nans:]
+}
+
+// floatRange returns the least and the greatest element of s,
+// ok is false if there are fewer than two distinct elements.
+func floatRange(s []float64) (lo, hi float64, ok bool) {
+	if len(s) < 2 {
+		return 0, 0, false
+	}
+	lo, hi = s[0], s[0]
+	for _, v := range s {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	return lo, hi, lo < hi
+}
+
+/*
+Complexity of bucket sort：
+	* Best: 	O(n)
+	* Average: 	O(n + n^2/k + k)
+	* Worst: 	O(nlog(n))
+	* Memory: 	O(n + k)
+	* Stable: 	With a stable Sort
+	* Wiki: 	https://en.wikipedia.org/wiki/Bucket_sort
+Shortcome from wiki:
+	Bucket sort, or bin sort, is a sorting algorithm that works by
+	distributing the elements of an array into a number of buckets.
+	Each bucket is then sorted individually, either using a different
+	sorting algorithm, or by recursively applying the bucket sorting
+	algorithm. When the input is uniformly distributed each bucket
+	holds few elements and the sort takes linear time, when most of
+	the elements fall in one bucket it takes the time of the inner sort.
+*/
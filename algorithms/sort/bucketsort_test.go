@@ -0,0 +1,121 @@
+package sort_test
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+)
+
+// floatInputs are uniform, skewed and degenerate inputs of the float sorts
+var floatInputs = map[string]func(n int) []float64{
+	"uniform": func(n int) []float64 {
+		s := make([]float64, n)
+		for i := range s {
+			s[i] = rand.Float64()*2000 - 1000
+		}
+		return s
+	},
+	"skewed": func(n int) []float64 {
+		s := make([]float64, n)
+		for i := range s {
+			s[i] = math.Exp(rand.Float64() * 50)
+		}
+		return s
+	},
+	"equal": func(n int) []float64 {
+		return make([]float64, n)
+	},
+	"nan and inf": func(n int) []float64 {
+		s := make([]float64, n)
+		for i := range s {
+			s[i] = rand.NormFloat64()
+		}
+		if n > 3 {
+			s[0], s[n/2], s[n-1] = math.NaN(), math.Inf(1), math.NaN()
+		}
+		return s
+	},
+	"huge range": func(n int) []float64 {
+		s := make([]float64, n)
+		for i := range s {
+			s[i] = (rand.Float64()*2 - 1) * math.MaxFloat64
+		}
+		return s
+	},
+}
+
+func checkFloats(t *testing.T, name string, s, src []float64) {
+	t.Helper()
+	want := slices.Clone(src)
+	slices.Sort(want)
+	if !slices.EqualFunc(s, want, func(a, b float64) bool { return a == b || math.IsNaN(a) && math.IsNaN(b) }) {
+		t.Fatalf("%s: wanted %v but get %v", name, want, s)
+	}
+}
+
+func TestBucketSort(t *testing.T) {
+	opts := []isort.BucketOptions{{}, {Buckets: 7}, {Buckets: 1 << 30, Sort: isort.InsertionSortOrdered[float64]}}
+	for name, gen := range floatInputs {
+		for _, n := range []int{0, 1, 2, 100, 10000} {
+			src := gen(n)
+			for _, o := range opts {
+				s := slices.Clone(src)
+				isort.BucketSort(s, o)
+				checkFloats(t, name, s, src)
+			}
+		}
+	}
+}
+
+func TestBucketSortClustered(t *testing.T) {
+	// all but one value fall in the first bucket, which must not take quadratic time
+	s := make([]float64, 200000)
+	for i := range s {
+		s[i] = rand.Float64()
+	}
+	s[0] = 1e12
+	src := slices.Clone(s)
+	isort.BucketSort(s, isort.BucketOptions{Buckets: 2})
+	checkFloats(t, "clustered", s, src)
+}
+
+func TestFlashSort(t *testing.T) {
+	for name, gen := range floatInputs {
+		for _, n := range []int{0, 1, 2, 3, 100, 10000} {
+			src := gen(n)
+			s := slices.Clone(src)
+			isort.FlashSort(s)
+			checkFloats(t, name, s, src)
+		}
+	}
+}
+
+func TestPigeonholeSort(t *testing.T) {
+	s := []int{5, -3, 5, 0, 12, -3}
+	if err := isort.PigeonholeSort(s, 0); err != nil || !slices.Equal(s, []int{-3, -3, 0, 5, 5, 12}) {
+		t.Errorf("wanted [-3 -3 0 5 5 12] but get %v, %v", s, err)
+	}
+	s = []int{math.MinInt, 0, math.MaxInt}
+	if err := isort.PigeonholeSort(s, 0); !errors.Is(err, isort.ErrRange) {
+		t.Errorf("wanted ErrRange but get %v", err)
+	}
+	if !slices.Equal(s, []int{math.MinInt, 0, math.MaxInt}) {
+		t.Errorf("refused sort changed its input to %v", s)
+	}
+	if err := isort.PigeonholeSort([]int{0, 16}, 16); !errors.Is(err, isort.ErrRange) {
+		t.Errorf("wanted ErrRange for 17 holes but get %v", err)
+	}
+}
+
+func TestCountingSortRange(t *testing.T) {
+	for _, src := range [][]int{nil, {7}, {1000, 1003, 1001, 1000}, {-5, 3, -5, 0}, {math.MaxInt, math.MinInt, 0, -1}} {
+		want := slices.Sorted(slices.Values(src))
+		if s := isort.CountingSort(src); !slices.Equal(s, want) {
+			t.Errorf("wanted %v but get %v", want, s)
+		}
+	}
+}
@@ -1,14 +1,25 @@
 package sort
 
-// CountingSort is a O(n+r) stable sorting algorithm just for a collection of small integers;
+// CountingSort is a O(n+r) stable sorting algorithm just for a collection of small integers,
+// r being the range of data. If more than MaxBuckets counts would be needed it sorts
+// with PdqSort instead.
 func CountingSort(data []int) []int {
-	mlo, mhi := 0, 0
+	sorted := make([]int, len(data))
+	if len(data) == 0 {
+		return sorted
+	}
+	mlo, mhi := data[0], data[0]
 	for _, v := range data {
 		mhi = max(v, mhi)
 		mlo = min(v, mlo)
 	}
+	// the difference of the extreme ints overflows an int but not a uint
+	if uint(mhi)-uint(mlo) >= MaxBuckets {
+		copy(sorted, data)
+		PdqSortOrdered(sorted)
+		return sorted
+	}
 	buckets := make([]int, mhi-mlo+1)
-	sorted := make([]int, len(data))
 	for _, v := range data {
 		buckets[v-mlo]++
 	}
@@ -22,8 +33,62 @@ func CountingSort(data []int) []int {
 	return sorted
 }
 
+// CountingSortSlice sorts s stably in the increasing order of key with a counting
+// sort, key is called once for each element. Counting needs keys instead of
+// comparisons, so there is no form for a Sortable: CountingSort takes []int. If
+// more than MaxBuckets counts would be needed it sorts with StableSortByKey instead.
+func CountingSortSlice[T any, K Integer](s []T, key func(T) K) {
+	if !countingSort(s, key) {
+		StableSortByKey(s, key)
+	}
+}
+
+// CountingSortOrdered sorts s in increasing order with CountingSortSlice
+func CountingSortOrdered[T Integer](s []T) {
+	CountingSortSlice(s, func(v T) T { return v })
+}
+
+// countingSort sorts s by counting its keys, it returns false and
+// leaves s unchanged if the range of the keys is too wide.
+func countingSort[T any, K Integer](s []T, key func(T) K) bool {
+	if len(s) < 2 {
+		return true
+	}
+	keys := make([]uint64, len(s))
+	for i, v := range s {
+		keys[i] = integerBits(key(v))
+	}
+	lo, hi := keys[0], keys[0]
+	for _, k := range keys {
+		if k < lo {
+			lo = k
+		}
+		if k > hi {
+			hi = k
+		}
+	}
+	if hi-lo >= MaxBuckets {
+		return false
+	}
+	// starts[k-lo] is the index of the next element with key k
+	starts := make([]int, hi-lo+2)
+	for _, k := range keys {
+		starts[k-lo+1]++
+	}
+	for i := 1; i < len(starts); i++ {
+		starts[i] += starts[i-1]
+	}
+	sorted := make([]T, len(s))
+	for i, k := range keys {
+		sorted[starts[k-lo]] = s[i]
+		starts[k-lo]++
+	}
+	copy(s, sorted)
+	return true
+}
+
 /*
-Complexity of bubble sort：
+Complexity of counting sort：
 	* Best: 	O(n + r)
 	* Average: 	O(n + r)
 	* Worst: 	O(n + r)
@@ -0,0 +1,263 @@
+package externalsort
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	manifestName = "manifest.json"
+	marksSuffix  = ".marks"
+)
+
+// manifest records the progress of a resumable sort in its work dir
+type manifest struct {
+	// Offset is the bytes of the input stored in runs
+	Offset int64 `json:"offset"`
+	// Split is set once the whole input is stored in runs
+	Split bool `json:"split"`
+	// Passes is the number of intermediate merges done
+	Passes int `json:"passes"`
+	// NextID is the id of the next run
+	NextID int `json:"next_id"`
+	// Runs are the complete runs which are not merged yet
+	Runs []manifestRun `json:"runs"`
+}
+
+// manifestRun is a complete run of a manifest
+type manifestRun struct {
+	ID   int   `json:"id"`
+	Size int64 `json:"size"`
+}
+
+func (m *manifest) add(id int, size int64) {
+	m.Runs = append(m.Runs, manifestRun{ID: id, Size: size})
+	m.NextID = max(m.NextID, id+1)
+}
+
+func (m *manifest) remove(ids []int) {
+	runs := m.Runs[:0]
+	for _, r := range m.Runs {
+		merged := false
+		for _, id := range ids {
+			merged = merged || r.ID == id
+		}
+		if !merged {
+			runs = append(runs, r)
+		}
+	}
+	m.Runs = runs
+}
+
+func (m *manifest) has(id int) bool {
+	for _, r := range m.Runs {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// save replaces the manifest in dir atomically
+func (m *manifest) save(dir string) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, manifestName+".tmp")
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, manifestName))
+}
+
+func loadManifest(dir string) (*manifest, error) {
+	b, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if err != nil {
+		return nil, err
+	}
+	m := new(manifest)
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("externalsort: bad manifest in %s: %v", dir, err)
+	}
+	return m, nil
+}
+
+// errWorkDirInUse reports a work dir which holds a sort to resume
+var errWorkDirInUse = errors.New("externalsort: work dir holds an interrupted sort, use Resume")
+
+// Resume continues the sort of ints interrupted in dir, which was the
+// Options.WorkDir of the sort. in must be the same input from its start,
+// the part already stored in runs is skipped. out receives the whole output,
+// so it must not keep what the interrupted sort wrote.
+func Resume(ctx context.Context, dir string, in io.Reader, out io.Writer, opts Options) error {
+	return ResumeFunc(ctx, dir, in, out, Decimal{}, lessInt, opts)
+}
+
+// ResumeFunc is like Resume for a sort made by SortFunc,
+// codec and less must be the ones of the interrupted sort.
+func ResumeFunc[T any](ctx context.Context, dir string, in io.Reader, out io.Writer, codec Codec[T], less func(a, b T) bool, opts Options) error {
+	m, err := loadManifest(dir)
+	if err != nil {
+		return err
+	}
+	opts.WorkDir = dir
+	s := &sorter[T]{dir: dir, codec: codec, less: less, opts: &opts, m: m, nextID: m.NextID,
+		p: newProgress(opts.Progress, in, m.Offset)}
+	if err := s.cleanup(); err != nil {
+		return err
+	}
+	if !m.Split {
+		if err := skip(in, m.Offset); err != nil {
+			return err
+		}
+	}
+	if err := s.sort(ctx, in, m.Offset, out); err != nil {
+		return err
+	}
+	return s.done()
+}
+
+// skip discards the first n bytes of r
+func skip(r io.Reader, n int64) error {
+	if sk, ok := r.(io.Seeker); ok {
+		_, err := sk.Seek(n, io.SeekStart)
+		return err
+	}
+	_, err := io.CopyN(io.Discard, r, n)
+	return err
+}
+
+// checkpoint applies update to the manifest and saves it,
+// it does nothing if the sort is not resumable.
+func (s *sorter[T]) checkpoint(update func(m *manifest)) error {
+	if s.m == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	update(s.m)
+	return s.m.save(s.dir)
+}
+
+// loadRuns returns the runs recorded in the manifest
+func (s *sorter[T]) loadRuns() ([]run[T], error) {
+	if s.m == nil {
+		return nil, nil
+	}
+	runs := make([]run[T], len(s.m.Runs))
+	for i, r := range s.m.Runs {
+		runs[i] = run[T]{id: r.ID, path: runPath(s.dir, r.ID), size: r.Size}
+		marks, err := readMarks(runs[i].path+marksSuffix, s.codec)
+		if err != nil {
+			return nil, err
+		}
+		runs[i].marks = marks
+	}
+	return runs, nil
+}
+
+// cleanup removes the files of runs and merges which were not complete
+func (s *sorter[T]) cleanup() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		switch {
+		case strings.HasPrefix(name, "run-"):
+			id, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "run-"), marksSuffix))
+			if err == nil && s.m.has(id) {
+				continue
+			}
+		case strings.HasPrefix(name, "part-"), name == manifestName+".tmp":
+		default:
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// done removes the files of a finished resumable sort
+func (s *sorter[T]) done() error {
+	if s.m == nil {
+		return nil
+	}
+	s.m.Runs = nil
+	if err := s.cleanup(); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(s.dir, manifestName)); err != nil {
+		return err
+	}
+	// keep a work dir which holds other files
+	os.Remove(s.dir)
+	return nil
+}
+
+// writeMarks stores marks at path, each mark is the uvarint
+// offset followed by the record encoded with codec.
+func writeMarks[T any](path string, marks []mark[T], codec Codec[T]) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	var buf [binary.MaxVarintLen64]byte
+	for _, m := range marks {
+		if _, err = w.Write(binary.AppendUvarint(buf[:0], uint64(m.offset))); err != nil {
+			break
+		}
+		if err = codec.Encode(w, m.record); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func readMarks[T any](path string, codec Codec[T]) ([]mark[T], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var marks []mark[T]
+	r := bufio.NewReader(f)
+	for {
+		offset, err := binary.ReadUvarint(r)
+		if err == io.EOF {
+			return marks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		record, err := codec.Decode(r)
+		if err != nil {
+			if err == io.EOF {
+				err = errShortRecord
+			}
+			return nil, err
+		}
+		marks = append(marks, mark[T]{record: record, offset: int64(offset)})
+	}
+}
@@ -0,0 +1,122 @@
+package externalsort
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var errCrash = errors.New("crash")
+
+// crashReader fails once n bytes are read
+type crashReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *crashReader) Read(p []byte) (int, error) {
+	if c.n <= 0 {
+		return 0, errCrash
+	}
+	if len(p) > c.n {
+		p = p[:c.n]
+	}
+	n, err := c.r.Read(p)
+	c.n -= n
+	return n, err
+}
+
+// crashWriter fails on every write
+type crashWriter struct{}
+
+func (crashWriter) Write(p []byte) (int, error) { return 0, errCrash }
+
+func resumeInput(n int) ([]int, string) {
+	keys := make([]int, n)
+	var b strings.Builder
+	for i := range keys {
+		keys[i] = rand.Intn(1 << 20)
+		fmt.Fprintf(&b, "%d\n", keys[i])
+	}
+	sort.Ints(keys)
+	return keys, b.String()
+}
+
+func checkOutput(t *testing.T, keys []int, out string) {
+	fields := strings.Fields(out)
+	if len(fields) != len(keys) {
+		t.Fatalf("sorted %d keys, get %d", len(keys), len(fields))
+	}
+	for i, f := range fields {
+		if k, _ := strconv.Atoi(f); k != keys[i] {
+			t.Fatalf("at %d wanted %v but get %v", i, keys[i], f)
+		}
+	}
+}
+
+func TestResumeSplit(t *testing.T) {
+	keys, in := resumeInput(20000)
+	dir := filepath.Join(t.TempDir(), "work")
+	opts := Options{WorkDir: dir, MemoryBudget: 32 << 10, FanIn: 4, Workers: 2}
+	ctx := context.Background()
+
+	err := Sort(ctx, &crashReader{r: strings.NewReader(in), n: len(in) * 2 / 3}, io.Discard, opts)
+	if !errors.Is(err, errCrash) {
+		t.Fatalf("wanted the crash but get %v", err)
+	}
+	m, err := loadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Split || m.Offset == 0 || len(m.Runs) == 0 {
+		t.Fatalf("wanted a checkpoint inside the input but get %+v", m)
+	}
+	// a run the crash left half written
+	os.WriteFile(runPath(dir, 999), []byte("12\n1"), 0o644)
+
+	var out bytes.Buffer
+	if err := Resume(ctx, dir, strings.NewReader(in), &out, opts); err != nil {
+		t.Fatal(err)
+	}
+	checkOutput(t, keys, out.String())
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("wanted the work dir removed but get %v", err)
+	}
+}
+
+func TestResumeMerge(t *testing.T) {
+	keys, in := resumeInput(20000)
+	dir := t.TempDir()
+	opts := Options{WorkDir: dir, MemoryBudget: 16 << 10, FanIn: 2}
+	ctx := context.Background()
+
+	if err := Sort(ctx, strings.NewReader(in), crashWriter{}, opts); !errors.Is(err, errCrash) {
+		t.Fatalf("wanted the crash but get %v", err)
+	}
+	m, err := loadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Split || m.Passes == 0 || len(m.Runs) != 2 {
+		t.Fatalf("wanted a checkpoint before the final merge but get %+v", m)
+	}
+	if err := Sort(ctx, strings.NewReader(in), io.Discard, opts); err != errWorkDirInUse {
+		t.Errorf("wanted %v but get %v", errWorkDirInUse, err)
+	}
+
+	// the input is not read again once it is split
+	var out bytes.Buffer
+	if err := Resume(ctx, dir, &crashReader{}, &out, opts); err != nil {
+		t.Fatal(err)
+	}
+	checkOutput(t, keys, out.String())
+}
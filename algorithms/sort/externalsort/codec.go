@@ -0,0 +1,240 @@
+package externalsort
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unsafe"
+)
+
+// Codec reads and writes the records of a sort, the input, the runs
+// on disk and the output all use the same codec so that runs can be
+// read back and merged again.
+type Codec[T any] interface {
+	// Encode writes record to w
+	Encode(w *bufio.Writer, record T) error
+	// Decode reads the next record from r, it returns io.EOF
+	// if r ends before a record starts.
+	Decode(r *bufio.Reader) (T, error)
+	// Size returns the bytes record refers to outside of its own value,
+	// e.g. the bytes of a string, it is used to honor the memory budget.
+	Size(record T) int
+}
+
+// ErrRecordSize is returned by Prefixed for a record longer than its MaxSize,
+// a corrupt length prefix reads as such a record.
+var ErrRecordSize = errors.New("externalsort: record too long")
+
+// errShortRecord reports a stream which ends in the middle of a record
+var errShortRecord = errors.New("externalsort: stream ends in the middle of a record")
+
+// Decimal is a Codec for ints written as decimal numbers one per line,
+// any white space separates the numbers on decode.
+type Decimal struct{}
+
+// Encode implements Codec
+func (Decimal) Encode(w *bufio.Writer, record int) error {
+	var buf [24]byte
+	b := strconv.AppendInt(buf[:0], int64(record), 10)
+	b = append(b, '\n')
+	_, err := w.Write(b)
+	return err
+}
+
+// Decode implements Codec
+func (Decimal) Decode(r *bufio.Reader) (int, error) {
+	var buf [24]byte
+	b := buf[:0]
+	for {
+		c, err := r.ReadByte()
+		if err == io.EOF && len(b) > 0 {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		if isSpace(c) {
+			if len(b) > 0 {
+				break
+			}
+			continue
+		}
+		b = append(b, c)
+	}
+	return strconv.Atoi(string(b))
+}
+
+// Size implements Codec
+func (Decimal) Size(record int) int { return 0 }
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\t' || c == '\r' || c == '\v' || c == '\f'
+}
+
+// Lines is a Codec for newline delimited text, records do not
+// contain the newline and a missing final newline is allowed.
+type Lines struct{}
+
+// Encode implements Codec
+func (Lines) Encode(w *bufio.Writer, record string) error {
+	if _, err := w.WriteString(record); err != nil {
+		return err
+	}
+	return w.WriteByte('\n')
+}
+
+// Decode implements Codec
+func (Lines) Decode(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err == io.EOF && len(line) > 0 {
+		return line, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return line[:len(line)-1], nil
+}
+
+// Size implements Codec
+func (Lines) Size(record string) int { return len(record) }
+
+// Varint is a Codec for ints written as zig-zag varints
+type Varint struct{}
+
+// Encode implements Codec
+func (Varint) Encode(w *bufio.Writer, record int) error {
+	var buf [binary.MaxVarintLen64]byte
+	_, err := w.Write(binary.AppendVarint(buf[:0], int64(record)))
+	return err
+}
+
+// Decode implements Codec
+func (Varint) Decode(r *bufio.Reader) (int, error) {
+	if _, err := r.Peek(1); err != nil {
+		return 0, err
+	}
+	v, err := binary.ReadVarint(r)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return 0, errShortRecord
+	}
+	return int(v), err
+}
+
+// Size implements Codec
+func (Varint) Size(record int) int { return 0 }
+
+// Integer is the constraint of the keys FixedLE can encode
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// FixedLE is a Codec for integers written as fixed width
+// little endian records of the size of T.
+type FixedLE[T Integer] struct{}
+
+func (FixedLE[T]) width() int { return int(unsafe.Sizeof(T(0))) }
+
+// Encode implements Codec
+func (c FixedLE[T]) Encode(w *bufio.Writer, record T) error {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(record))
+	_, err := w.Write(buf[:c.width()])
+	return err
+}
+
+// Decode implements Codec
+func (c FixedLE[T]) Decode(r *bufio.Reader) (T, error) {
+	var buf [8]byte
+	n := c.width()
+	if _, err := io.ReadFull(r, buf[:n]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = errShortRecord
+		}
+		return 0, err
+	}
+	// sign extend negative values of signed types
+	if T(0)-1 < 0 && buf[n-1]&0x80 != 0 {
+		for i := n; i < len(buf); i++ {
+			buf[i] = 0xff
+		}
+	}
+	return T(binary.LittleEndian.Uint64(buf[:])), nil
+}
+
+// Size implements Codec
+func (FixedLE[T]) Size(record T) int { return 0 }
+
+// DefaultMaxRecord is the longest record of a Prefixed without a MaxSize
+const DefaultMaxRecord = 64 << 20
+
+// Prefixed is a Codec for byte records each written after its
+// uvarint length, records are ordered by the bytes of Key.
+type Prefixed struct {
+	// Key extracts the sort key of a record, the whole record if nil
+	Key func(record []byte) []byte
+	// MaxSize bounds the length of a record, DefaultMaxRecord if 0, so
+	// that a corrupt length is an error instead of a huge allocation.
+	MaxSize int
+}
+
+// maxSize returns the longest record c encodes or decodes
+func (c Prefixed) maxSize() uint64 {
+	if c.MaxSize > 0 {
+		return uint64(c.MaxSize)
+	}
+	return DefaultMaxRecord
+}
+
+// Encode implements Codec
+func (c Prefixed) Encode(w *bufio.Writer, record []byte) error {
+	if uint64(len(record)) > c.maxSize() {
+		return fmt.Errorf("%w: %d bytes over the limit %d", ErrRecordSize, len(record), c.maxSize())
+	}
+	var buf [binary.MaxVarintLen64]byte
+	if _, err := w.Write(binary.AppendUvarint(buf[:0], uint64(len(record)))); err != nil {
+		return err
+	}
+	_, err := w.Write(record)
+	return err
+}
+
+// Decode implements Codec
+func (c Prefixed) Decode(r *bufio.Reader) ([]byte, error) {
+	if _, err := r.Peek(1); err != nil {
+		return nil, err
+	}
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = errShortRecord
+		}
+		return nil, err
+	}
+	if n > c.maxSize() {
+		return nil, fmt.Errorf("%w: %d bytes over the limit %d", ErrRecordSize, n, c.maxSize())
+	}
+	record := make([]byte, n)
+	if _, err := io.ReadFull(r, record); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = errShortRecord
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+// Size implements Codec
+func (Prefixed) Size(record []byte) int { return len(record) }
+
+// Less orders records by the bytes of their keys
+func (c Prefixed) Less(a, b []byte) bool {
+	if c.Key == nil {
+		return bytes.Compare(a, b) < 0
+	}
+	return bytes.Compare(c.Key(a), c.Key(b)) < 0
+}
@@ -0,0 +1,170 @@
+package externalsort_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/man-fish/goalgorithms/algorithms/sort/externalsort"
+)
+
+func roundTrip[T any](t *testing.T, codec externalsort.Codec[T], records []T, equal func(a, b T) bool) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	for _, r := range records {
+		if err := codec.Encode(w, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Flush()
+
+	r := bufio.NewReader(&buf)
+	for i, want := range records {
+		got, err := codec.Decode(r)
+		if err != nil {
+			t.Fatalf("decode record %d: %v", i, err)
+		}
+		if !equal(got, want) {
+			t.Fatalf("record %d wanted %v but get %v", i, want, got)
+		}
+	}
+	if _, err := codec.Decode(r); err != io.EOF {
+		t.Errorf("wanted io.EOF after the last record but get %v", err)
+	}
+}
+
+func eq[T comparable](a, b T) bool { return a == b }
+
+func TestCodecRoundTrip(t *testing.T) {
+	ints := []int{0, -1, 1, 9999, math.MaxInt64, math.MinInt64, math.MaxInt32}
+	roundTrip[int](t, externalsort.Decimal{}, ints, eq[int])
+	roundTrip[int](t, externalsort.Varint{}, ints, eq[int])
+	roundTrip[int](t, externalsort.FixedLE[int]{}, ints, eq[int])
+	roundTrip[int16](t, externalsort.FixedLE[int16]{}, []int16{0, -1, math.MinInt16, math.MaxInt16}, eq[int16])
+	roundTrip[uint32](t, externalsort.FixedLE[uint32]{}, []uint32{0, 1, math.MaxUint32}, eq[uint32])
+	roundTrip[string](t, externalsort.Lines{}, []string{"", "a b", "\tx", "last"}, eq[string])
+	roundTrip[[]byte](t, externalsort.Prefixed{}, [][]byte{{}, []byte("\n\x00"), bytes.Repeat([]byte("z"), 300)}, bytes.Equal)
+}
+
+func TestCodecShortRecord(t *testing.T) {
+	r := bufio.NewReader(bytes.NewReader([]byte{1, 2, 3}))
+	if _, err := (externalsort.FixedLE[int64]{}).Decode(r); err == nil || err == io.EOF {
+		t.Errorf("wanted a short record error but get %v", err)
+	}
+	r = bufio.NewReader(bytes.NewReader([]byte{5, 'a'}))
+	if _, err := (externalsort.Prefixed{}).Decode(r); err == nil || err == io.EOF {
+		t.Errorf("wanted a short record error but get %v", err)
+	}
+}
+
+func TestCodecRecordSize(t *testing.T) {
+	huge := binary.AppendUvarint(nil, math.MaxUint64)
+	r := bufio.NewReader(bytes.NewReader(append(huge, 'a')))
+	if _, err := (externalsort.Prefixed{}).Decode(r); !errors.Is(err, externalsort.ErrRecordSize) {
+		t.Errorf("wanted ErrRecordSize but get %v", err)
+	}
+	codec := externalsort.Prefixed{MaxSize: 4}
+	r = bufio.NewReader(bytes.NewReader([]byte{5, 'a', 'b', 'c', 'd', 'e'}))
+	if _, err := codec.Decode(r); !errors.Is(err, externalsort.ErrRecordSize) {
+		t.Errorf("wanted ErrRecordSize for 5 bytes but get %v", err)
+	}
+	w := bufio.NewWriter(io.Discard)
+	if err := codec.Encode(w, []byte("abcde")); !errors.Is(err, externalsort.ErrRecordSize) {
+		t.Errorf("wanted ErrRecordSize on encode but get %v", err)
+	}
+	r = bufio.NewReader(bytes.NewReader([]byte{4, 'a', 'b', 'c', 'd'}))
+	if rec, err := codec.Decode(r); err != nil || string(rec) != "abcd" {
+		t.Errorf("wanted abcd but get %q, %v", rec, err)
+	}
+}
+
+func encodeAll[T any](codec externalsort.Codec[T], records []T) []byte {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	for _, r := range records {
+		codec.Encode(w, r)
+	}
+	w.Flush()
+	return buf.Bytes()
+}
+
+func decodeAll[T any](t *testing.T, codec externalsort.Codec[T], data []byte) []T {
+	var records []T
+	r := bufio.NewReader(bytes.NewReader(data))
+	for {
+		rec, err := codec.Decode(r)
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, rec)
+	}
+}
+
+func TestSortFuncCodecs(t *testing.T) {
+	opts := externalsort.Options{TempDir: t.TempDir(), MemoryBudget: 9 << 10}
+	ctx := context.Background()
+
+	ints := make([]int, 5000)
+	for i := range ints {
+		ints[i] = rand.Int() - rand.Int()
+	}
+	want := slices.Clone(ints)
+	sort.Ints(want)
+	for _, codec := range []externalsort.Codec[int]{externalsort.Varint{}, externalsort.FixedLE[int]{}} {
+		var out bytes.Buffer
+		if err := externalsort.SortFunc(ctx, bytes.NewReader(encodeAll(codec, ints)), &out, codec, func(a, b int) bool { return a < b }, opts); err != nil {
+			t.Fatal(err)
+		}
+		if got := decodeAll(t, codec, out.Bytes()); !slices.Equal(got, want) {
+			t.Errorf("%T sorted %d records, get %d out of order", codec, len(want), len(got))
+		}
+	}
+
+	lines := make([]string, 3000)
+	for i := range lines {
+		lines[i] = strings.Repeat(string(rune('a'+rand.Intn(26))), rand.Intn(40))
+	}
+	var out bytes.Buffer
+	if err := externalsort.SortFunc[string](ctx, strings.NewReader(strings.Join(lines, "\n")), &out, externalsort.Lines{}, func(a, b string) bool { return a < b }, opts); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(lines)
+	if got := decodeAll[string](t, externalsort.Lines{}, out.Bytes()); !slices.Equal(got, lines) {
+		t.Errorf("lines are not sorted")
+	}
+}
+
+func TestSortFuncPrefixedKey(t *testing.T) {
+	// records are "key:payload", sorted by key only
+	codec := externalsort.Prefixed{Key: func(r []byte) []byte { return r[:bytes.IndexByte(r, ':')] }}
+	records := make([][]byte, 2000)
+	for i := range records {
+		records[i] = []byte(string(rune('a'+rand.Intn(26))) + ":" + strings.Repeat("p", rand.Intn(50)))
+	}
+	var out bytes.Buffer
+	opts := externalsort.Options{TempDir: t.TempDir(), MemoryBudget: 9 << 10}
+	if err := externalsort.SortFunc[[]byte](context.Background(), bytes.NewReader(encodeAll[[]byte](codec, records)), &out, codec, codec.Less, opts); err != nil {
+		t.Fatal(err)
+	}
+	got := decodeAll[[]byte](t, codec, out.Bytes())
+	if len(got) != len(records) {
+		t.Fatalf("sorted %d records, get %d", len(records), len(got))
+	}
+	for i := 1; i < len(got); i++ {
+		if codec.Less(got[i], got[i-1]) {
+			t.Fatalf("%q sorts after %q", got[i-1], got[i])
+		}
+	}
+}
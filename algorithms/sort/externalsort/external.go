@@ -1,81 +1,131 @@
-package external
+/*
+Package externalsort implements an external merge sort:
+	External sorting is a class of sorting algorithms that can handle
+	massive amounts of data. External sorting is required when the data
+	being sorted do not fit into the main memory of a computing device
+	(usually RAM) and instead they must reside in the slower external
+	memory, usually a disk drive.
+
+	The sort is done in two phases. In the sorting phase, chunks of data
+	small enough to fit in main memory are read, sorted, and written out
+	to a temporary file (a run). This package uses replacement selection
+	on a loser tree to produce the runs, which makes runs twice as long
+	as the memory on average for random input. In the merge phase, the
+	sorted runs are combined into a single larger file with a k-way merge
+	on the same loser tree.
+WikiPage:
+	* https://en.wikipedia.org/wiki/External_sorting
+*/
+package externalsort
 
 import (
-	"fmt"
-	"io"
+	"context"
 
 	"github.com/man-fish/goalgorithms/algorithms/sort/externalsort/losertree"
 )
 
+func lessInt(a, b int) bool { return a < b }
+
+// RunWriter receives sorted records, Spliter writes one segment
+// after the other and Combiner writes a single segment.
+type RunWriter[T any] interface {
+	// Write appends record to the current segment
+	Write(record T) error
+	// EndRun closes the current segment, following records start a new one
+	EndRun() error
+}
+
 // Spliter is a big file Spliter
-type Spliter struct {
-	t      *losertree.LoserTree
-	output io.Writer
+type Spliter[T any] struct {
+	t      *losertree.LoserTree[T]
+	less   func(a, b T) bool
+	output RunWriter[T]
 }
 
-// NewSpliter is the constructor of spliter
-func NewSpliter(n int, s losertree.Sourcer) *Spliter {
-	spt := new(Spliter)
-	spt.t = losertree.New(n, s)
+// NewSpliter is the constructor of spliter with a workarea of n records
+func NewSpliter[T any](n int, s losertree.Sourcer[T], less func(a, b T) bool, output RunWriter[T]) *Spliter[T] {
+	spt := new(Spliter[T])
+	spt.t = losertree.New(n, s, less)
+	spt.less = less
+	spt.output = output
 	return spt
 }
 
-// ReplaceSelection make k segments to output
-func (s *Spliter) ReplaceSelection() {
-	scur, smax := 1, 1
-	// cur segement id and segemnt max
-	for scur <= smax {
+// ReplaceSelection make k segments to output, it stops with the error of ctx
+// once ctx is done.
+func (s *Spliter[T]) ReplaceSelection(ctx context.Context) error {
+	// cur segement id
+	scur := 1
+	for !s.t.Leaf[s.t.Winner()].Done {
 		// make segment to output
-		s.buildSegement(scur, &smax)
-		fmt.Fprintf(s.output, "%v", losertree.EOS)
+		if err := s.buildSegement(ctx, scur); err != nil {
+			return err
+		}
 		// add end of segment
+		if err := s.output.EndRun(); err != nil {
+			return err
+		}
 		scur = s.t.Leaf[s.t.Winner()].S
 	}
+	return nil
 }
 
-func (s *Spliter) buildSegement(scur int, smax *int) {
-	for w := s.t.Winner(); s.t.Leaf[w].S == scur; {
-		// get cur minmax id
-		i := w
+func (s *Spliter[T]) buildSegement(ctx context.Context, scur int) error {
+	n := 0
+	for w := s.t.Winner(); !s.t.Leaf[w].Done && s.t.Leaf[w].S == scur; w = s.t.Winner() {
+		if n++; n%checkEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
 		// get cur min value of minmax
-		minmax := s.t.Leaf[i].K
+		minmax := s.t.Leaf[w].K
 		// write to output
-		fmt.Fprintf(s.output, "%v", minmax)
-		if res := s.t.Input.Next(i); res == losertree.EOF {
-			s.t.Leaf[i].K = losertree.EOF
-			s.t.Leaf[i].S = *smax + 1
+		if err := s.output.Write(minmax); err != nil {
+			return err
+		}
+		if res, ok := s.t.Input.Next(w); !ok {
+			s.t.Leaf[w].Done = true
 		} else {
-			s.t.Leaf[i].K = res
-			if res < minmax {
-				s.t.Leaf[i].S = scur + 1
-				*smax = scur + 1
+			s.t.Leaf[w].K = res
+			if s.less(res, minmax) {
+				s.t.Leaf[w].S = scur + 1
 			} else {
-				s.t.Leaf[i].S = scur
+				s.t.Leaf[w].S = scur
 			}
 		}
-		s.t.Contest(i)
+		s.t.Contest(w)
 	}
+	return nil
 }
 
 // Combiner implement to merge k segments
-type Combiner struct {
-	t      *losertree.LoserTree
-	output io.Writer
+type Combiner[T any] struct {
+	t      *losertree.LoserTree[T]
+	output RunWriter[T]
 }
 
 // NewCombiner is the constructor of Combiner
-func NewCombiner(n int, s losertree.Sourcer) *Combiner {
-	c := new(Combiner)
-	c.t = losertree.New(n, s)
+func NewCombiner[T any](n int, s losertree.Sourcer[T], less func(a, b T) bool, output RunWriter[T]) *Combiner[T] {
+	c := new(Combiner[T])
+	c.t = losertree.New(n, s, less)
+	c.output = output
 	return c
 }
 
-// KMerge sort the k sources and write to output
-func (c *Combiner) KMerge() {
-	winner := c.t.Winner()
-	for min := c.t.Leaf[winner].K; min != losertree.EOF; {
-		fmt.Fprintf(c.output, "%v", min)
-		c.t.Leaf[winner].K = c.t.Input.Next(winner)
-		c.t.Contest(winner)
+// KMerge sort the k sources and write to output as one segment,
+// it stops with the error of ctx once ctx is done.
+func (c *Combiner[T]) KMerge(ctx context.Context) error {
+	n := 0
+	for k, ok := c.t.Pop(); ok; k, ok = c.t.Pop() {
+		if n++; n%checkEvery == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if err := c.output.Write(k); err != nil {
+			return err
+		}
 	}
+	return c.output.EndRun()
 }
@@ -1,84 +1,101 @@
 package losertree
 
-import (
-	"math"
-)
-
-const (
-	// EOF marks the end of file
-	EOF = math.MaxInt32
-	// EOS marks the end of a segement in output file
-	EOS = 9999
-)
-
 // Sourcer represent the leaf of the loser tree and where the data come from
-type Sourcer interface {
-	// Next returns next num from source with index i (segement)
-	// if no nums remain return EOF, All
-	// EOF means merge over
-	Next(i int) int
+type Sourcer[T any] interface {
+	// Next returns next num from source with index i (segement),
+	// ok is false if the source has no nums remain. Every int is
+	// a valid num, the end of a source never takes a key value.
+	Next(i int) (key T, ok bool)
 }
 
 // Enrty is source from file sys
-type Enrty struct {
+type Enrty[T any] struct {
 	// s for segement num with default 1
 	S int
 	// k for key
-	K int
+	K T
+	// Done marks a leaf whose source is exhausted, it loses every contest
+	Done bool
 }
 
 // LoserTree implements a min heap datastructure
 // it is divided to branch and leaf two parts
 // len(branch) = len(leaf) - 1
-type LoserTree struct {
+type LoserTree[T any] struct {
 	// branch[0] store the winner, branch[1:k] store the losers
 	branch []int
 	// leaf represent workarea
-	Leaf []Enrty
+	Leaf []Enrty[T]
 	// size represent workarea count
 	size  int
-	Input Sourcer
+	Input Sourcer[T]
+	// less reports whether key a should sort before key b
+	less func(a, b T) bool
 }
 
 // New is the constructor of LoserTree with n data source it has
 // n branch, the first leaf store a final loser in compartions
 // n+1 leaf, the last leaf store the min value for compare.
-func New(n int, s Sourcer) *LoserTree {
-	loser := &LoserTree{
+func New[T any](n int, s Sourcer[T], less func(a, b T) bool) *LoserTree[T] {
+	loser := &LoserTree[T]{
 		branch: make([]int, n),
-		Leaf:   make([]Enrty, n),
+		Leaf:   make([]Enrty[T], n),
 		size:   n,
 		Input:  s,
+		less:   less,
 	}
 	loser.build()
 	return loser
 }
 
-// build fill workspace
-func (t *LoserTree) build() {
+// build fill workspace, the zero segment of an unfilled leaf
+// makes it smaller than any key until it gets its own key.
+func (t *LoserTree[T]) build() {
 	for i := 0; i < t.size; i++ {
-		t.branch[i], t.Leaf[i] = 0, Enrty{S: 0, K: 0}
+		t.branch[i], t.Leaf[i] = 0, Enrty[T]{S: 0}
 	}
 
 	for i := t.size - 1; i >= 0; i-- {
-		t.Leaf[i].K = t.Input.Next(i)
-		t.Leaf[i].S = 1
+		k, ok := t.Input.Next(i)
+		t.Leaf[i] = Enrty[T]{S: 1, K: k, Done: !ok}
 		t.Contest(i)
 	}
 }
 
 // Winner returns cur competition winner
-func (t *LoserTree) Winner() int {
+func (t *LoserTree[T]) Winner() int {
 	return t.branch[0]
 }
 
+// Peek returns the key Pop returns next without removing it
+func (t *LoserTree[T]) Peek() (key T, ok bool) {
+	if t.size == 0 || t.Leaf[t.Winner()].Done {
+		return key, false
+	}
+	return t.Leaf[t.Winner()].K, true
+}
+
+// Pop returns the smallest key and refills its leaf from the source,
+// ok is false once every source is exhausted. Pop is meant for a plain
+// k-way merge where all leaves stay in the same segment.
+func (t *LoserTree[T]) Pop() (key T, ok bool) {
+	if t.size == 0 || t.Leaf[t.Winner()].Done {
+		return key, false
+	}
+	w := t.Winner()
+	key = t.Leaf[w].K
+	t.Leaf[w].K, ok = t.Input.Next(w)
+	t.Leaf[w].Done = !ok
+	t.Contest(w)
+	return key, true
+}
+
 // Contest get the minVal from k sources and fill the tree with losers (larger num)
-func (t *LoserTree) Contest(i int) {
+func (t *LoserTree[T]) Contest(i int) {
 	p := (i + t.size) / 2
 
 	for p > 0 {
-		pa := t.branch[p]
-		if t.Leaf[i].S > t.Leaf[pa].S || (t.Leaf[i].S == t.Leaf[pa].S && t.Leaf[i].K > t.Leaf[pa].K) {
+		if t.loses(i, t.branch[p]) {
 			// i always stores the smallest num
 			// t.branch always store the larger num(loser)
 			t.branch[p], i = i, t.branch[p]
@@ -89,49 +106,22 @@ func (t *LoserTree) Contest(i int) {
 	t.branch[0] = i
 }
 
-// // KMerge sort the k sources and write to output
-// func (t *LoserTree) KMerge() {
-// 	winner := t.branch[0]
-// 	for min := t.Leaf[winner].k; min != EOF; {
-// 		fmt.Fprintf(t.output, "%v", min)
-// 		t.Leaf[winner].k = t.input.Next(winner)
-// 		t.Contest(winner)
-// 	}
-// }
-
-// // ReplaceSelection make k segments to output
-// func (t *LoserTree) ReplaceSelection() {
-// 	scur, smax := 1, 1
-// 	// cur segement id and segemnt max
-// 	for scur <= smax {
-// 		// make segment to output
-// 		t.buildSegement(scur, &smax)
-// 		fmt.Fprintf(t.output, "%v", EOS)
-// 		// add end of segment
-// 		scur = t.Leaf[t.branch[0]].s
-// 	}
-// }
-
-// func (t *LoserTree) buildSegement(s int, smax *int) {
-// 	for t.Leaf[t.branch[0]].s == s {
-// 		// get cur minmax id
-// 		i := t.branch[0]
-// 		// get cur min value of minmax
-// 		minmax := t.Leaf[i].k
-// 		// write to output
-// 		fmt.Fprintf(t.output, "%v", minmax)
-// 		if res := t.input.Next(i); res == EOF {
-// 			t.Leaf[i].k = EOF
-// 			t.Leaf[i].s = *smax + 1
-// 		} else {
-// 			t.Leaf[i].k = res
-// 			if res < minmax {
-// 				t.Leaf[i].s = s + 1
-// 				*smax = s + 1
-// 			} else {
-// 				t.Leaf[i].s = s
-// 			}
-// 		}
-// 		t.Contest(i)
-// 	}
-// }
+// loses reports whether leaf i loses the contest against leaf j,
+// exhausted leaves lose against all others and then compare segment and key,
+// equal keys are won by the lower leaf so that merging is stable.
+func (t *LoserTree[T]) loses(i, j int) bool {
+	if i == j {
+		return false
+	}
+	a, b := &t.Leaf[i], &t.Leaf[j]
+	if a.Done || b.Done {
+		return a.Done && !b.Done
+	}
+	if a.S != b.S {
+		return a.S > b.S
+	}
+	if t.less(b.K, a.K) {
+		return true
+	}
+	return i > j && !t.less(a.K, b.K)
+}
@@ -0,0 +1,89 @@
+package losertree
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"testing"
+)
+
+type sliceSourcer[T any] [][]T
+
+func (s sliceSourcer[T]) Next(i int) (T, bool) {
+	var k T
+	if len(s[i]) == 0 {
+		return k, false
+	}
+	k = s[i][0]
+	s[i] = s[i][1:]
+	return k, true
+}
+
+func lessInt(a, b int) bool { return a < b }
+
+func TestPop(t *testing.T) {
+	// keys that used to be sentinels must be merged as plain keys
+	src := sliceSourcer[int]{
+		{math.MinInt64, -1, 9999, math.MaxInt32},
+		{},
+		{0, 9999, math.MaxInt32, math.MaxInt64},
+		{9999},
+	}
+	var want []int
+	for _, s := range src {
+		want = append(want, s...)
+	}
+	sort.Ints(want)
+
+	tr := New(len(src), src, lessInt)
+	var got []int
+	for k, ok := tr.Pop(); ok; k, ok = tr.Pop() {
+		got = append(got, k)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("wanted %v but get %v", want, got)
+	}
+}
+
+func TestPopEmpty(t *testing.T) {
+	tr := New(0, sliceSourcer[int]{}, lessInt)
+	if k, ok := tr.Pop(); ok {
+		t.Errorf("wanted nothing but get %v", k)
+	}
+}
+
+type record struct {
+	ts      int
+	payload string
+}
+
+func TestMerge(t *testing.T) {
+	seqs := [][]record{
+		{{1, "a0"}, {3, "a1"}, {3, "a2"}, {7, "a3"}},
+		{{2, "b0"}, {3, "b1"}},
+		{},
+		{{1, "d0"}, {3, "d1"}, {9, "d2"}},
+	}
+	want := []string{"a0", "d0", "b0", "a1", "a2", "b1", "d1", "a3", "d2"}
+
+	var got []string
+	less := func(a, b record) bool { return a.ts < b.ts }
+	for r := range Merge(less, slices.Values(seqs[0]), slices.Values(seqs[1]), slices.Values(seqs[2]), slices.Values(seqs[3])) {
+		got = append(got, r.payload)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("wanted %v but get %v", want, got)
+	}
+}
+
+func TestMergeBreak(t *testing.T) {
+	n := 0
+	for range Merge(lessInt, slices.Values([]int{1, 3, 5}), slices.Values([]int{2, 4})) {
+		if n++; n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("wanted 3 keys but get %v", n)
+	}
+}
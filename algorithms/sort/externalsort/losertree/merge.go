@@ -0,0 +1,32 @@
+package losertree
+
+import "iter"
+
+// seqSourcer pulls the keys of each source sequence on demand
+type seqSourcer[T any] struct {
+	next []func() (T, bool)
+}
+
+// Next implements Sourcer
+func (s *seqSourcer[T]) Next(i int) (T, bool) {
+	return s.next[i]()
+}
+
+// Merge yields the keys of sorted sequences seqs in the order of less,
+// keys that compare equal are yielded in the order of their sequences.
+func Merge[T any](less func(a, b T) bool, seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		src := &seqSourcer[T]{next: make([]func() (T, bool), len(seqs))}
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+			src.next[i] = next
+		}
+		t := New[T](len(seqs), src, less)
+		for k, ok := t.Pop(); ok; k, ok = t.Pop() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
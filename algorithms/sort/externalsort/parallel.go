@@ -0,0 +1,469 @@
+package externalsort
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+)
+
+// records attaches the methods of sort.Sortable to a slice of records
+type records[T any] struct {
+	s    []T
+	less func(a, b T) bool
+}
+
+func (r *records[T]) Len() int           { return len(r.s) }
+func (r *records[T]) Less(i, j int) bool { return r.less(r.s[i], r.s[j]) }
+func (r *records[T]) Swap(i, j int)      { r.s[i], r.s[j] = r.s[j], r.s[i] }
+func (r *records[T]) Equal(i, j int) bool {
+	return !r.less(r.s[i], r.s[j]) && !r.less(r.s[j], r.s[i])
+}
+
+var _ isort.Sortable = (*records[int])(nil)
+
+// sortRecords sorts s in memory with the sorts of algorithms/sort
+func sortRecords[T any](s []T, less func(a, b T) bool) {
+	isort.PdqSort(&records[T]{s: s, less: less})
+}
+
+// chunk is a part of the input that becomes the run with id,
+// end is the offset of the input after the chunk.
+type chunk[T any] struct {
+	id      int
+	end     int64
+	records []T
+}
+
+// countReader counts the bytes read from r
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// readChunk reads records until they take limit bytes, it returns
+// io.EOF with the last records once the input is exhausted.
+func readChunk[T any](ctx context.Context, r *bufio.Reader, codec Codec[T], limit int64) ([]T, error) {
+	var s []T
+	for used := int64(0); used < limit || len(s) == 0; {
+		if len(s)%checkEvery == checkEvery-1 {
+			if err := ctx.Err(); err != nil {
+				return s, err
+			}
+		}
+		k, err := codec.Decode(r)
+		if err != nil {
+			return s, err
+		}
+		s = append(s, k)
+		used += int64(unsafe.Sizeof(k)) + int64(codec.Size(k))
+	}
+	return s, nil
+}
+
+// writeChunk sorts the records of c and writes them as a run
+func (s *sorter[T]) writeChunk(ctx context.Context, c chunk[T]) (run[T], error) {
+	sortRecords(c.records, s.less)
+	w := &runSet[T]{dir: s.dir, first: c.id, size: minBufferSize, codec: s.codec, persist: s.m != nil, p: s.p, split: true}
+	for i, k := range c.records {
+		if i%checkEvery == checkEvery-1 {
+			if err := ctx.Err(); err != nil {
+				w.EndRun()
+				return run[T]{}, err
+			}
+		}
+		if err := w.Write(k); err != nil {
+			w.EndRun()
+			return run[T]{}, err
+		}
+	}
+	if err := w.EndRun(); err != nil {
+		return run[T]{}, err
+	}
+	return w.runs[0], nil
+}
+
+// splitParallel cuts in, which starts at byte offset of the input, into chunks
+// that fit the memory budget, opts.Workers goroutines sort the chunks in memory
+// and write each one as a run. Every run holds a contiguous part of the input,
+// so a resumable sort checkpoints the runs as soon as all runs before are done.
+func (s *sorter[T]) splitParallel(ctx context.Context, in io.Reader, offset int64) ([]run[T], error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	workers := s.opts.workers()
+	// every worker holds a chunk while the next one is read
+	limit := s.opts.workarea() / int64(workers+1)
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		first error
+		// runs are indexed by chunk id from base, the first
+		// unsaved ones are saved once the runs before are done.
+		base    = s.nextID
+		runs    []run[T]
+		ends    []int64
+		unsaved = base
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if first == nil {
+			first = err
+			cancel()
+		}
+		mu.Unlock()
+	}
+	done := func(c chunk[T], r run[T]) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for len(runs) <= c.id-base {
+			runs = append(runs, run[T]{})
+			ends = append(ends, -1)
+		}
+		runs[c.id-base], ends[c.id-base] = r, c.end
+		from := unsaved
+		for unsaved-base < len(ends) && ends[unsaved-base] >= 0 {
+			unsaved++
+		}
+		if from == unsaved {
+			return nil
+		}
+		return s.checkpoint(func(m *manifest) {
+			for id := from; id < unsaved; id++ {
+				m.add(id, runs[id-base].size)
+			}
+			m.Offset = ends[unsaved-1-base]
+		})
+	}
+	chunks := make(chan chunk[T])
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range chunks {
+				r, err := s.writeChunk(ctx, c)
+				if err == nil {
+					err = done(c, r)
+				}
+				if err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	cr := &countReader{r: in}
+	r := bufio.NewReaderSize(cr, minBufferSize)
+	for {
+		records, err := readChunk(ctx, r, s.codec, limit)
+		// the records before a read error are not stored, the
+		// offset of a failed decode may be in the middle of a record
+		if len(records) > 0 && (err == nil || err == io.EOF) {
+			c := chunk[T]{id: s.nextID, end: offset + cr.n - int64(r.Buffered()), records: records}
+			s.nextID++
+			s.p.read(int64(len(records)), c.end-offset)
+			select {
+			case chunks <- c:
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fail(err)
+			break
+		}
+	}
+	close(chunks)
+	wg.Wait()
+	return runs, first
+}
+
+// keyRange is the half open range [lo, hi) of records of a merge
+// partition, a nil bound leaves the range open on that side.
+type keyRange[T any] struct {
+	lo, hi *T
+}
+
+// partition cuts the records of runs into at most n key ranges of about the same
+// size, it picks the bounds among the marks which sample the runs evenly.
+func partition[T any](runs []run[T], less func(a, b T) bool, n int) []keyRange[T] {
+	var samples []T
+	for _, r := range runs {
+		for _, m := range r.marks {
+			samples = append(samples, m.record)
+		}
+	}
+	sortRecords(samples, less)
+
+	var ranges []keyRange[T]
+	var lo *T
+	for i := 1; i < n; i++ {
+		hi := &samples[i*len(samples)/n]
+		// skip empty ranges
+		if lo != nil && !less(*lo, *hi) {
+			continue
+		}
+		ranges = append(ranges, keyRange[T]{lo: lo, hi: hi})
+		lo = hi
+	}
+	return append(ranges, keyRange[T]{lo: lo})
+}
+
+// rangeSourcer feeds a combiner with the records of runs inside a key range
+type rangeSourcer[T any] struct {
+	*runSourcer[T]
+	less func(a, b T) bool
+	r    keyRange[T]
+}
+
+// openRange opens runs positioned at the last mark before the range
+func openRange[T any](runs []run[T], size int, codec Codec[T], less func(a, b T) bool, r keyRange[T]) (*rangeSourcer[T], error) {
+	src, err := openRuns(runs, size, codec)
+	if err != nil {
+		return nil, err
+	}
+	if r.lo != nil {
+		for i, run := range runs {
+			var offset int64
+			for _, m := range run.marks {
+				if !less(m.record, *r.lo) {
+					break
+				}
+				offset = m.offset
+			}
+			if _, err := src.files[i].Seek(offset, io.SeekStart); err != nil {
+				src.Close()
+				return nil, err
+			}
+			src.readers[i].Reset(src.files[i])
+		}
+	}
+	return &rangeSourcer[T]{runSourcer: src, less: less, r: r}, nil
+}
+
+// Next implements losertree.Sourcer
+func (s *rangeSourcer[T]) Next(i int) (T, bool) {
+	for {
+		k, ok := s.runSourcer.Next(i)
+		if !ok {
+			return k, false
+		}
+		if s.r.lo != nil && s.less(k, *s.r.lo) {
+			continue
+		}
+		if s.r.hi != nil && !s.less(k, *s.r.hi) {
+			var zero T
+			return zero, false
+		}
+		return k, true
+	}
+}
+
+// combineRange merges the records of runs inside r into w
+func (s *sorter[T]) combineRange(ctx context.Context, runs []run[T], r keyRange[T], w RunWriter[T], size int) error {
+	src, err := openRange(runs, size, s.codec, s.less, r)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if err := NewCombiner(len(runs), src, s.less, w).KMerge(ctx); err != nil {
+		return err
+	}
+	return src.err
+}
+
+// combineParallel merges runs into out with a worker per key range, the first
+// error cancels the other workers. If out is a file which can be written at
+// offsets each range is merged straight into its own segment of out, otherwise
+// see combineParts.
+func (s *sorter[T]) combineParallel(ctx context.Context, runs []run[T], out io.Writer) error {
+	ranges := partition(runs, s.less, s.opts.workers())
+	size := s.opts.bufferSize(len(ranges) * len(runs))
+	w, base, ok := seekable(out)
+	if !ok {
+		return s.combineParts(ctx, runs, ranges, out, size)
+	}
+
+	starts, err := s.rangeStarts(runs, ranges)
+	if err != nil {
+		return err
+	}
+	err = eachRange(ctx, len(ranges), func(ctx context.Context, p int) error {
+		sw := newStreamWriter(io.NewOffsetWriter(w, base+starts[p]), size, s.codec, s.p)
+		return s.combineRange(ctx, runs, ranges[p], sw, size)
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Seek(base+starts[len(ranges)], io.SeekStart)
+	return err
+}
+
+// combineParts merges the first range into out while the others are merged into
+// files under dir at the same time, which are then appended to out in the order
+// of their ranges. It is the fallback for outputs like pipes which cannot be
+// written at offsets, it copies all records but those of the first range once more.
+func (s *sorter[T]) combineParts(ctx context.Context, runs []run[T], ranges []keyRange[T], out io.Writer, size int) error {
+	parts := make([]string, len(ranges))
+	for p := 1; p < len(ranges); p++ {
+		parts[p] = filepath.Join(s.dir, fmt.Sprintf("part-%03d", p))
+	}
+	err := eachRange(ctx, len(ranges), func(ctx context.Context, p int) error {
+		if p == 0 {
+			return s.combineRange(ctx, runs, ranges[0], newStreamWriter(out, size, s.codec, s.p), size)
+		}
+		f, err := os.Create(parts[p])
+		if err != nil {
+			return err
+		}
+		err = s.combineRange(ctx, runs, ranges[p], newStreamWriter(f, size, s.codec, s.p), size)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, part := range parts[1:] {
+		if err := appendFile(out, part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eachRange calls work for ranges 0 to n-1 each on its own goroutine,
+// it returns the first error, which cancels the context of the others.
+func eachRange(ctx context.Context, n int, work func(ctx context.Context, p int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		first error
+	)
+	for p := 0; p < n; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			if err := work(ctx, p); err != nil {
+				mu.Lock()
+				if first == nil {
+					first = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}(p)
+	}
+	wg.Wait()
+	return first
+}
+
+// writerSeeker is an output which can be written at offsets, like an *os.File
+type writerSeeker interface {
+	io.WriterAt
+	io.Seeker
+}
+
+// seekable returns out with its current offset if it can be written at offsets
+func seekable(out io.Writer) (writerSeeker, int64, bool) {
+	w, ok := out.(writerSeeker)
+	if !ok {
+		return nil, 0, false
+	}
+	// pipes do not seek and files opened to append refuse WriteAt
+	base, err := w.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, 0, false
+	}
+	if _, err := w.WriteAt(nil, base); err != nil {
+		return nil, 0, false
+	}
+	return w, base, true
+}
+
+// rangeStarts returns the offset of each range in the merged output and its
+// size last. The codec encodes a record to the same bytes in the runs and in
+// the output, so a range starts after the bytes of the records before its lower
+// bound in every run.
+func (s *sorter[T]) rangeStarts(runs []run[T], ranges []keyRange[T]) ([]int64, error) {
+	starts := make([]int64, len(ranges)+1)
+	for _, r := range runs {
+		for p := 1; p < len(ranges); p++ {
+			offset, err := s.boundOffset(r, *ranges[p].lo)
+			if err != nil {
+				return nil, err
+			}
+			starts[p] += offset
+		}
+		starts[len(ranges)] += r.size
+	}
+	return starts, nil
+}
+
+// boundOffset returns the offset in the file of r of its first record which is
+// not less than bound, it reads from the last mark before bound.
+func (s *sorter[T]) boundOffset(r run[T], bound T) (int64, error) {
+	var offset int64
+	for _, m := range r.marks {
+		if !s.less(m.record, bound) {
+			break
+		}
+		offset = m.offset
+	}
+	f, err := os.Open(r.path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	cr := &countReader{r: f}
+	br := bufio.NewReaderSize(cr, minBufferSize)
+	for {
+		at := offset + cr.n - int64(br.Buffered())
+		record, err := s.codec.Decode(br)
+		if err == io.EOF {
+			return r.size, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if !s.less(record, bound) {
+			return at, nil
+		}
+	}
+}
+
+// appendFile copies the file at path to w and removes it
+func appendFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
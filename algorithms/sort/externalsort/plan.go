@@ -0,0 +1,85 @@
+package externalsort
+
+import (
+	"sort"
+
+	"github.com/man-fish/goalgorithms/datastructures/compare"
+	"github.com/man-fish/goalgorithms/datastructures/priorityqueue"
+)
+
+// mergeStep merges the runs with ids inputs into the run with id output,
+// the ids of runs made by merges follow the ids of the initial runs.
+type mergeStep struct {
+	inputs []int
+	output int
+}
+
+// planItem is a run waiting in the planner queue,
+// smaller runs have a higher priority.
+type planItem struct {
+	id   int
+	size int64
+}
+
+// Equal implements compare.Comparable
+func (p *planItem) Equal(c compare.Comparable) bool {
+	return p.CompareTo(c) == 0
+}
+
+// CompareTo implements compare.Comparable
+func (p *planItem) CompareTo(c compare.Comparable) int {
+	o := c.(*planItem)
+	switch {
+	case p.size < o.size || (p.size == o.size && p.id < o.id):
+		return 1
+	case p.size == o.size && p.id == o.id:
+		return 0
+	}
+	return -1
+}
+
+// planMerge plans the merge of runs with sizes merging at most fanIn
+// runs at a time, the last step makes the final output. Like a k-ary
+// Huffman code it always merges the smallest runs first, which keeps
+// the bytes rewritten by intermediate passes minimal. The first step
+// merges just enough runs so that every later step merges fanIn runs.
+func planMerge(sizes []int64, fanIn int) []mergeStep {
+	n := len(sizes)
+	if n == 0 {
+		return nil
+	}
+	if fanIn < 2 {
+		fanIn = 2
+	}
+	q := priorityqueue.New(n)
+	for i, size := range sizes {
+		q.Add(&planItem{id: i, size: size})
+	}
+
+	var plan []mergeStep
+	k := n
+	if n > fanIn {
+		k = (n-2)%(fanIn-1) + 2
+	}
+	for next := n; ; next++ {
+		step := mergeStep{output: next}
+		var size int64
+		for i := 0; i < k; i++ {
+			item := q.Pop().(*planItem)
+			step.inputs = append(step.inputs, item.id)
+			size += item.size
+		}
+		// inputs in id order make each step, and so the output,
+		// deterministic. It does not make the sort stable: a step may
+		// merge runs which are not adjacent in the input, equal records
+		// of one step only keep the order of its inputs, as the loser
+		// tree breaks ties by leaf.
+		sort.Ints(step.inputs)
+		plan = append(plan, step)
+		if q.IsEmpty() {
+			return plan
+		}
+		q.Add(&planItem{id: next, size: size})
+		k = fanIn
+	}
+}
@@ -0,0 +1,78 @@
+package externalsort
+
+import "testing"
+
+// mergeCost returns the bytes written by the intermediate passes of plan
+func mergeCost(sizes []int64, plan []mergeStep) int64 {
+	sizes = append([]int64(nil), sizes...)
+	var cost int64
+	for i, step := range plan {
+		var size int64
+		for _, id := range step.inputs {
+			size += sizes[id]
+		}
+		sizes = append(sizes, size)
+		if i < len(plan)-1 {
+			cost += size
+		}
+	}
+	return cost
+}
+
+func TestPlanMerge(t *testing.T) {
+	for _, fanIn := range []int{2, 3, 4, 64} {
+		for n := 1; n < 200; n += 7 {
+			sizes := make([]int64, n)
+			for i := range sizes {
+				sizes[i] = int64(i*37%11 + 1)
+			}
+			plan := planMerge(sizes, fanIn)
+			merged := make([]bool, n+len(plan))
+			for i, step := range plan {
+				if len(step.inputs) > fanIn && n > 1 {
+					t.Fatalf("fan-in %d: step %d merges %d runs", fanIn, i, len(step.inputs))
+				}
+				if step.output != n+i {
+					t.Fatalf("fan-in %d: step %d makes run %d", fanIn, i, step.output)
+				}
+				for _, id := range step.inputs {
+					if id >= step.output || merged[id] {
+						t.Fatalf("fan-in %d: run %d merged twice or before it exists", fanIn, id)
+					}
+					merged[id] = true
+				}
+			}
+			for id := 0; id < n+len(plan)-1; id++ {
+				if !merged[id] {
+					t.Fatalf("fan-in %d: run %d of %d is never merged", fanIn, id, n)
+				}
+			}
+		}
+	}
+}
+
+func TestPlanMergeSmallestFirst(t *testing.T) {
+	// with fan-in 3 the three smallest runs are merged first,
+	// the final pass merges the result with the two large runs.
+	sizes := []int64{100, 1, 5, 2, 50}
+	plan := planMerge(sizes, 3)
+	if len(plan) != 2 {
+		t.Fatalf("wanted 2 steps but get %v", plan)
+	}
+	if got := mergeCost(sizes, plan); got != 8 {
+		t.Errorf("wanted cost 13 but get %v with %v", got, plan)
+	}
+}
+
+func TestPlanMergeEvenFirstPass(t *testing.T) {
+	// 6 runs with fan-in 3 need a first pass of 2 runs so that
+	// the remaining passes are full: {1,2} then {3,3,4} then {10,50,100}.
+	sizes := []int64{100, 1, 4, 2, 50, 3}
+	plan := planMerge(sizes, 3)
+	if len(plan) != 3 || len(plan[0].inputs) != 2 {
+		t.Fatalf("wanted 3 steps starting with 2 runs but get %v", plan)
+	}
+	if got := mergeCost(sizes, plan); got != 13 {
+		t.Errorf("wanted cost 13 but get %v with %v", got, plan)
+	}
+}
@@ -0,0 +1,113 @@
+package externalsort
+
+import (
+	"io"
+	"io/fs"
+	"sync"
+)
+
+const (
+	// checkEvery is the number of records between two checks of the context
+	checkEvery = 1 << 10
+	// reportEvery is the number of records between two progress reports
+	reportEvery = 1 << 16
+)
+
+// Progress is a snapshot of the work done by a sort
+type Progress struct {
+	// RecordsRead is the number of records read from the input
+	RecordsRead int64
+	// BytesRead is the bytes of the input read
+	BytesRead int64
+	// Runs is the number of runs made from the input
+	Runs int
+	// BytesWritten is the bytes written to runs and to the output
+	BytesWritten int64
+	// Pass is the merge pass in progress counted from 1, 0 while the input is split
+	Pass int
+	// Passes is the number of merge passes of the plan, 0 while the input is split
+	Passes int
+	// Remaining estimates the bytes the sort has still to write, -1 if it is
+	// unknown. While the input is split the estimate only holds for inputs of a
+	// known size and leaves out the intermediate passes, which are planned once
+	// all runs are made.
+	Remaining int64
+}
+
+// progress collects the Progress of a sort and reports it to fn,
+// the reports are serialized so fn needs no locking.
+type progress struct {
+	mu sync.Mutex
+	fn func(Progress)
+	p  Progress
+	// inputSize is the size of the input, -1 if unknown
+	inputSize int64
+	// planned is the bytes the merge plan writes and merged
+	// the value of BytesWritten when the merge started
+	planned, merged int64
+}
+
+func newProgress(fn func(Progress), in io.Reader, offset int64) *progress {
+	size := inputSize(in)
+	if size >= 0 {
+		size -= offset
+	}
+	return &progress{fn: fn, inputSize: size, merged: -1}
+}
+
+// inputSize returns the bytes of in, -1 if it is unknown
+func inputSize(in io.Reader) int64 {
+	switch r := in.(type) {
+	case interface{ Size() int64 }:
+		return r.Size()
+	case interface{ Stat() (fs.FileInfo, error) }:
+		if fi, err := r.Stat(); err == nil && fi.Mode().IsRegular() {
+			return fi.Size()
+		}
+	}
+	return -1
+}
+
+// update applies f to the progress and reports it
+func (p *progress) update(f func(p *Progress)) {
+	if p == nil || p.fn == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f(&p.p)
+	switch {
+	case p.merged >= 0:
+		p.p.Remaining = max(p.planned-(p.p.BytesWritten-p.merged), 0)
+	case p.inputSize >= 0:
+		// the rest of the input goes to runs and the whole input to the output
+		p.p.Remaining = max(p.inputSize-p.p.BytesRead, 0) + p.inputSize
+	default:
+		p.p.Remaining = -1
+	}
+	p.fn(p.p)
+}
+
+// wrote adds n bytes written
+func (p *progress) wrote(n int64) {
+	p.update(func(p *Progress) { p.BytesWritten += n })
+}
+
+// read adds n records and sets the bytes of the input read
+func (p *progress) read(n, bytes int64) {
+	p.update(func(p *Progress) {
+		p.RecordsRead += n
+		p.BytesRead = bytes
+	})
+}
+
+// plan starts the merge of passes which write planned bytes
+func (p *progress) plan(passes int, planned int64) {
+	if p == nil || p.fn == nil {
+		return
+	}
+	p.mu.Lock()
+	p.planned, p.merged = planned, p.p.BytesWritten
+	p.mu.Unlock()
+	p.update(func(p *Progress) { p.Passes = passes })
+}
@@ -0,0 +1,86 @@
+package externalsort
+
+import (
+	"context"
+	"iter"
+
+	"github.com/man-fish/goalgorithms/algorithms/sort/externalsort/losertree"
+)
+
+// Reducer is a Combiner which collapses each group of equal records, those
+// for which neither is less than the other, into one output record.
+type Reducer[T, R any] struct {
+	t      *losertree.LoserTree[T]
+	less   func(a, b T) bool
+	reduce func(key T, values iter.Seq[T]) R
+	output RunWriter[R]
+}
+
+// NewReducer is the constructor of Reducer, reduce is called with the first
+// record of each group and a sequence of all its records in merge order.
+// The sequence pulls the records from the sources as it is ranged over, so
+// a group never has to fit in memory, and it is only valid during the call.
+func NewReducer[T, R any](n int, s losertree.Sourcer[T], less func(a, b T) bool, reduce func(key T, values iter.Seq[T]) R, output RunWriter[R]) *Reducer[T, R] {
+	r := new(Reducer[T, R])
+	r.t = losertree.New(n, s, less)
+	r.less = less
+	r.reduce = reduce
+	r.output = output
+	return r
+}
+
+// KMerge sort the k sources and write the reduction of each group
+// to output as one segment, it stops with the error of ctx once ctx is done.
+func (r *Reducer[T, R]) KMerge(ctx context.Context) error {
+	n := 0
+	for key, ok := r.t.Peek(); ok; key, ok = r.t.Peek() {
+		var err error
+		values := func(yield func(T) bool) {
+			if err != nil {
+				return
+			}
+			for k, ok := r.t.Peek(); ok && !r.less(key, k); k, ok = r.t.Peek() {
+				if n++; n%checkEvery == 0 {
+					if err = ctx.Err(); err != nil {
+						return
+					}
+				}
+				r.t.Pop()
+				if !yield(k) {
+					return
+				}
+			}
+		}
+		res := r.reduce(key, values)
+		// drop what reduce left of the group
+		for range values {
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.output.Write(res); err != nil {
+			return err
+		}
+	}
+	return r.output.EndRun()
+}
+
+// Unique is a reduce func keeping the first record of each group
+func Unique[T any](key T, values iter.Seq[T]) T {
+	return key
+}
+
+// Counted is a record with the number of times it occurs
+type Counted[T any] struct {
+	Key   T
+	Count int
+}
+
+// Count is a reduce func counting the records of each group
+func Count[T any](key T, values iter.Seq[T]) Counted[T] {
+	n := 0
+	for range values {
+		n++
+	}
+	return Counted[T]{Key: key, Count: n}
+}
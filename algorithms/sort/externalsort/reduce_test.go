@@ -0,0 +1,113 @@
+package externalsort
+
+import (
+	"context"
+	"iter"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sliceSourcer serves the records of sorted slices
+type sliceSourcer[T any] [][]T
+
+func (s sliceSourcer[T]) Next(i int) (T, bool) {
+	var k T
+	if len(s[i]) == 0 {
+		return k, false
+	}
+	k, s[i] = s[i][0], s[i][1:]
+	return k, true
+}
+
+// sliceWriter collects the records of its segments
+type sliceWriter[T any] struct {
+	records []T
+	runs    int
+}
+
+func (w *sliceWriter[T]) Write(record T) error {
+	w.records = append(w.records, record)
+	return nil
+}
+
+func (w *sliceWriter[T]) EndRun() error {
+	w.runs++
+	return nil
+}
+
+func reduceInput() sliceSourcer[int] {
+	return sliceSourcer[int]{{1, 1, 3, 5}, {1, 2, 5}, {}, {2, 5, 5, 9}}
+}
+
+func TestReducerUnique(t *testing.T) {
+	w := &sliceWriter[int]{}
+	if err := NewReducer(4, reduceInput(), lessInt, Unique[int], w).KMerge(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2, 3, 5, 9}; !reflect.DeepEqual(w.records, want) || w.runs != 1 {
+		t.Errorf("wanted %v in one run but get %v in %d", want, w.records, w.runs)
+	}
+}
+
+func TestReducerCount(t *testing.T) {
+	w := &sliceWriter[Counted[int]]{}
+	if err := NewReducer(4, reduceInput(), lessInt, Count[int], w).KMerge(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []Counted[int]{{1, 3}, {2, 2}, {3, 1}, {5, 4}, {9, 1}}
+	if !reflect.DeepEqual(w.records, want) {
+		t.Errorf("wanted %v but get %v", want, w.records)
+	}
+}
+
+func TestReducerReduce(t *testing.T) {
+	type entry struct {
+		key string
+		n   int
+	}
+	src := sliceSourcer[entry]{
+		{{"a", 1}, {"b", 2}, {"b", 3}},
+		{{"a", 10}, {"c", 4}},
+		{{"b", 20}, {"c", 5}},
+	}
+	less := func(a, b entry) bool { return a.key < b.key }
+	// the values of a group come in merge order, reduce may stop early
+	var first []string
+	join := func(key entry, values iter.Seq[entry]) string {
+		var b strings.Builder
+		for v := range values {
+			if b.Len() == 0 {
+				first = append(first, v.key)
+			}
+			b.WriteString(strings.Repeat(v.key, v.n%10+1))
+			if v.n >= 10 {
+				break
+			}
+		}
+		return b.String()
+	}
+	w := &sliceWriter[string]{}
+	if err := NewReducer(3, src, less, join, w).KMerge(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"aaa", "bbbbbbbb", "ccccccccccc"}; !reflect.DeepEqual(w.records, want) {
+		t.Errorf("wanted %v but get %v", want, w.records)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(first, want) {
+		t.Errorf("wanted groups %v but get %v", want, first)
+	}
+}
+
+func TestReducerCancel(t *testing.T) {
+	keys := make([]int, 10*checkEvery)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := &sliceWriter[Counted[int]]{}
+	if err := NewReducer(1, sliceSourcer[int]{keys}, lessInt, Count[int], w).KMerge(ctx); err != context.Canceled {
+		t.Errorf("wanted %v but get %v", context.Canceled, err)
+	}
+	if w.runs != 0 {
+		t.Errorf("wanted no segment but get %d", w.runs)
+	}
+}
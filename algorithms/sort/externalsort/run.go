@@ -0,0 +1,267 @@
+package externalsort
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// inputSourcer feeds the spliter with records from the input,
+// the records read ahead to size the workarea are served first.
+type inputSourcer[T any] struct {
+	pending []T
+	cr      *countReader
+	r       *bufio.Reader
+	codec   Codec[T]
+	err     error
+	p       *progress
+	// n is the number of records served, reported the ones in the progress
+	n, reported int64
+}
+
+func newInputSourcer[T any](in io.Reader, codec Codec[T], p *progress) *inputSourcer[T] {
+	cr := &countReader{r: in}
+	return &inputSourcer[T]{cr: cr, r: bufio.NewReaderSize(cr, minBufferSize), codec: codec, p: p}
+}
+
+// report adds the records served since the last report to the progress
+func (s *inputSourcer[T]) report() {
+	s.p.read(s.n-s.reported, s.cr.n-int64(s.r.Buffered()))
+	s.reported = s.n
+}
+
+// Next implements losertree.Sourcer
+func (s *inputSourcer[T]) Next(i int) (T, bool) {
+	if len(s.pending) > 0 {
+		k := s.pending[0]
+		s.pending = s.pending[1:]
+		if len(s.pending) == 0 {
+			s.pending = nil
+		}
+		s.served()
+		return k, true
+	}
+	var k T
+	if s.err != nil {
+		return k, false
+	}
+	k, err := s.codec.Decode(s.r)
+	if err != nil {
+		if err != io.EOF {
+			s.err = err
+		}
+		return k, false
+	}
+	s.served()
+	return k, true
+}
+
+func (s *inputSourcer[T]) served() {
+	if s.n++; s.n%reportEvery == 0 {
+		s.report()
+	}
+}
+
+// streamWriter is a RunWriter encoding records to a stream
+type streamWriter[T any] struct {
+	cw    *countWriter
+	w     *bufio.Writer
+	codec Codec[T]
+	p     *progress
+	count int
+	// reported is the bytes written in the progress
+	reported int64
+}
+
+func newStreamWriter[T any](w io.Writer, size int, codec Codec[T], p *progress) *streamWriter[T] {
+	cw := &countWriter{w: w}
+	return &streamWriter[T]{cw: cw, w: bufio.NewWriterSize(cw, size), codec: codec, p: p}
+}
+
+// Write implements RunWriter
+func (s *streamWriter[T]) Write(record T) error {
+	if s.count++; s.count%reportEvery == 0 {
+		s.report()
+	}
+	return s.codec.Encode(s.w, record)
+}
+
+// EndRun implements RunWriter
+func (s *streamWriter[T]) EndRun() error {
+	err := s.w.Flush()
+	s.report()
+	return err
+}
+
+func (s *streamWriter[T]) report() {
+	n := s.cw.n + int64(s.w.Buffered())
+	s.p.wrote(n - s.reported)
+	s.reported = n
+}
+
+// sampleEvery is the number of records between two marks of a run
+const sampleEvery = 1024
+
+// mark is a record of a run and its offset in the run file
+type mark[T any] struct {
+	record T
+	offset int64
+}
+
+// run is a sorted run on disk
+type run[T any] struct {
+	id   int
+	path string
+	size int64
+	// marks samples every sampleEvery-th record of the run
+	marks []mark[T]
+}
+
+// remove removes the files of the run
+func (r run[T]) remove() {
+	os.Remove(r.path)
+	os.Remove(r.path + marksSuffix)
+}
+
+// runIDs returns the ids of runs
+func runIDs[T any](runs []run[T]) []int {
+	ids := make([]int, len(runs))
+	for i, r := range runs {
+		ids[i] = r.id
+	}
+	return ids
+}
+
+// countWriter counts the bytes written to w
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// runSet is a RunWriter storing each segment in its own file under dir,
+// the files are named by the run ids which start at first. The marks
+// of each run are stored next to it if persist is set. The bytes written
+// go to p, which also counts the runs if the runs are made from the input.
+type runSet[T any] struct {
+	dir     string
+	first   int
+	size    int
+	codec   Codec[T]
+	persist bool
+	p       *progress
+	split   bool
+	runs    []run[T]
+	f       *os.File
+	cw      *countWriter
+	w       *bufio.Writer
+	count   int
+	// reported is the bytes of the current run written in the progress
+	reported int64
+}
+
+func runPath(dir string, id int) string {
+	return filepath.Join(dir, fmt.Sprintf("run-%06d", id))
+}
+
+// Write implements RunWriter
+func (r *runSet[T]) Write(record T) error {
+	if r.w == nil {
+		id := r.first + len(r.runs)
+		path := runPath(r.dir, id)
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		r.runs = append(r.runs, run[T]{id: id, path: path})
+		r.f, r.cw, r.count, r.reported = f, &countWriter{w: f}, 0, 0
+		r.w = bufio.NewWriterSize(r.cw, r.size)
+	}
+	if r.count > 0 && r.count%reportEvery == 0 {
+		n := r.cw.n + int64(r.w.Buffered())
+		r.p.wrote(n - r.reported)
+		r.reported = n
+	}
+	if r.count%sampleEvery == 0 {
+		cur := &r.runs[len(r.runs)-1]
+		cur.marks = append(cur.marks, mark[T]{record: record, offset: r.cw.n + int64(r.w.Buffered())})
+	}
+	r.count++
+	return r.codec.Encode(r.w, record)
+}
+
+// EndRun implements RunWriter
+func (r *runSet[T]) EndRun() error {
+	if r.w == nil {
+		return nil
+	}
+	cur := &r.runs[len(r.runs)-1]
+	err := r.w.Flush()
+	cur.size = r.cw.n
+	r.p.update(func(p *Progress) {
+		p.BytesWritten += cur.size - r.reported
+		if r.split {
+			p.Runs++
+		}
+	})
+	if cerr := r.f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && r.persist {
+		err = writeMarks(cur.path+marksSuffix, cur.marks, r.codec)
+	}
+	r.f, r.cw, r.w = nil, nil, nil
+	return err
+}
+
+// runSourcer feeds the combiner with records from run files
+type runSourcer[T any] struct {
+	files   []*os.File
+	readers []*bufio.Reader
+	codec   Codec[T]
+	err     error
+}
+
+func openRuns[T any](runs []run[T], size int, codec Codec[T]) (*runSourcer[T], error) {
+	s := &runSourcer[T]{codec: codec}
+	for _, r := range runs {
+		f, err := os.Open(r.path)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		s.files = append(s.files, f)
+		s.readers = append(s.readers, bufio.NewReaderSize(f, size))
+	}
+	return s, nil
+}
+
+// Next implements losertree.Sourcer
+func (s *runSourcer[T]) Next(i int) (T, bool) {
+	k, err := s.codec.Decode(s.readers[i])
+	if err != nil {
+		if err != io.EOF && s.err == nil {
+			s.err = err
+		}
+		return k, false
+	}
+	return k, true
+}
+
+// Close closes all run files
+func (s *runSourcer[T]) Close() error {
+	var err error
+	for _, f := range s.files {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
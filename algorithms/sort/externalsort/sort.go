@@ -0,0 +1,278 @@
+package externalsort
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"unsafe"
+
+	"github.com/man-fish/goalgorithms/algorithms/sort/externalsort/losertree"
+)
+
+const (
+	// DefaultMemoryBudget is the memory budget used when Options.MemoryBudget is 0
+	DefaultMemoryBudget = 64 << 20
+	// DefaultFanIn is the fan-in used when Options.FanIn is 0
+	DefaultFanIn = 64
+	// minBufferSize and maxBufferSize bound the io buffer of each run file
+	minBufferSize = 4 << 10
+	maxBufferSize = 1 << 20
+)
+
+// Options configures Sort
+type Options struct {
+	// TempDir is where runs are written, os.TempDir() if empty
+	TempDir string
+	// WorkDir makes the sort resumable, the runs and the manifest of the
+	// sort are kept in WorkDir, which is left behind if the sort fails so
+	// that Resume can continue it. It is created if it does not exist.
+	// Runs of a resumable sort are sorted chunks of the input instead of
+	// replacement selection runs, whose records come from all over the input.
+	WorkDir string
+	// MemoryBudget is the max bytes the sort uses for records and io buffers
+	MemoryBudget int64
+	// FanIn is the max number of runs merged at a time, DefaultFanIn if 0
+	FanIn int
+	// Workers is the number of goroutines sorting chunks of the input and
+	// merging key ranges of the final pass, 1 keeps the sort sequential
+	// with replacement selection.
+	Workers int
+	// Progress is called with the progress of the sort when runs are
+	// written, when merge passes start and every few thousand records.
+	// The calls never overlap but they may come from several goroutines,
+	// so Progress must not block for long.
+	Progress func(Progress)
+}
+
+func (o *Options) fanIn() int {
+	if o.FanIn <= 0 {
+		return DefaultFanIn
+	}
+	return max(o.FanIn, 2)
+}
+
+func (o *Options) workers() int {
+	return max(o.Workers, 1)
+}
+
+func (o *Options) budget() int64 {
+	if o.MemoryBudget <= 0 {
+		return DefaultMemoryBudget
+	}
+	return o.MemoryBudget
+}
+
+// workarea returns the bytes of records replacement selection may hold
+func (o *Options) workarea() int64 {
+	return o.budget() - 2*minBufferSize
+}
+
+// bufferSize shares the budget among n open files
+func (o *Options) bufferSize(n int) int {
+	size := o.budget() / int64(n+1)
+	if size < minBufferSize {
+		return minBufferSize
+	}
+	if size > maxBufferSize {
+		return maxBufferSize
+	}
+	return int(size)
+}
+
+// leafSize returns the memory a record of type T takes in the loser tree
+func leafSize[T any]() int64 {
+	return int64(unsafe.Sizeof(losertree.Enrty[T]{}) + unsafe.Sizeof(int(0)))
+}
+
+// Sort reads whitespace separated integers from in and writes them
+// to out in ascending order, one per line. Runs are produced with
+// replacement selection into temporary files under opts.TempDir and
+// merged with a loser tree in as many passes as opts.FanIn requires,
+// so in may be much larger than memory. The sort stops with the
+// error of ctx once ctx is done.
+func Sort(ctx context.Context, in io.Reader, out io.Writer, opts Options) error {
+	return SortFunc(ctx, in, out, Decimal{}, lessInt, opts)
+}
+
+// SortFunc is like Sort for the records of any codec, it writes
+// the records of in to out in the order of less.
+func SortFunc[T any](ctx context.Context, in io.Reader, out io.Writer, codec Codec[T], less func(a, b T) bool, opts Options) error {
+	s := &sorter[T]{codec: codec, less: less, opts: &opts, p: newProgress(opts.Progress, in, 0)}
+	if opts.WorkDir != "" {
+		if err := os.MkdirAll(opts.WorkDir, 0o755); err != nil {
+			return err
+		}
+		if _, err := os.Stat(filepath.Join(opts.WorkDir, manifestName)); err == nil {
+			return errWorkDirInUse
+		}
+		s.dir, s.m = opts.WorkDir, &manifest{}
+		if err := s.m.save(s.dir); err != nil {
+			return err
+		}
+	} else {
+		dir, err := os.MkdirTemp(opts.TempDir, "externalsort-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		s.dir = dir
+	}
+
+	if err := s.sort(ctx, in, 0, out); err != nil {
+		return err
+	}
+	return s.done()
+}
+
+// sorter holds the state of a sort
+type sorter[T any] struct {
+	dir   string
+	codec Codec[T]
+	less  func(a, b T) bool
+	opts  *Options
+	// m is the manifest of a resumable sort, nil if the sort is not resumable
+	m  *manifest
+	mu sync.Mutex
+	// nextID is the id of the next run
+	nextID int
+	p      *progress
+}
+
+// sort splits in, which starts at byte offset of the input, into runs
+// and merges them with the runs that are already done into out.
+func (s *sorter[T]) sort(ctx context.Context, in io.Reader, offset int64, out io.Writer) error {
+	runs, err := s.loadRuns()
+	if err != nil {
+		return err
+	}
+	if s.m == nil || !s.m.Split {
+		var more []run[T]
+		if s.m == nil && s.opts.workers() == 1 {
+			more, err = s.split(ctx, in)
+		} else {
+			// runs of a resumable sort must hold contiguous parts of the input
+			more, err = s.splitParallel(ctx, in, offset)
+		}
+		if err != nil {
+			return err
+		}
+		runs = append(runs, more...)
+		if err := s.checkpoint(func(m *manifest) { m.Split = true }); err != nil {
+			return err
+		}
+	}
+	return s.merge(ctx, runs, out)
+}
+
+// split writes the runs of in to dir
+func (s *sorter[T]) split(ctx context.Context, in io.Reader) ([]run[T], error) {
+	src := newInputSourcer(in, s.codec, s.p)
+	// read ahead until the workarea is full so that
+	// the tree is sized by the records it really holds
+	for used, leaf := int64(0), leafSize[T](); used < s.opts.workarea() || len(src.pending) == 0; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		k, err := s.codec.Decode(src.r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		src.pending = append(src.pending, k)
+		used += leaf + int64(s.codec.Size(k))
+	}
+	if len(src.pending) == 0 {
+		return nil, nil
+	}
+
+	runs := s.newRunSet(minBufferSize)
+	runs.split = true
+	spt := NewSpliter(len(src.pending), src, s.less, runs)
+	if err := spt.ReplaceSelection(ctx); err != nil {
+		runs.EndRun()
+		return nil, err
+	}
+	src.report()
+	s.nextID += len(runs.runs)
+	return runs.runs, src.err
+}
+
+// newRunSet returns a RunWriter for runs numbered from s.nextID
+func (s *sorter[T]) newRunSet(size int) *runSet[T] {
+	return &runSet[T]{dir: s.dir, first: s.nextID, size: size, codec: s.codec, persist: s.m != nil, p: s.p}
+}
+
+// merge merges runs into out following the plan of planMerge,
+// the inputs of each intermediate pass are removed once merged.
+func (s *sorter[T]) merge(ctx context.Context, runs []run[T], out io.Writer) error {
+	sizes := make([]int64, len(runs))
+	for i, r := range runs {
+		sizes[i] = r.size
+	}
+	plan := planMerge(sizes, s.opts.fanIn())
+	// each step writes as many bytes as its inputs hold
+	var planned int64
+	for _, step := range plan {
+		var size int64
+		for _, id := range step.inputs {
+			size += sizes[id]
+		}
+		sizes = append(sizes, size)
+		planned += size
+	}
+	s.p.plan(len(plan), planned)
+	for i, step := range plan {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s.p.update(func(p *Progress) { p.Pass = i + 1 })
+		inputs := make([]run[T], len(step.inputs))
+		for j, id := range step.inputs {
+			inputs[j] = runs[id]
+		}
+		size := s.opts.bufferSize(len(inputs))
+
+		if i == len(plan)-1 && s.opts.workers() > 1 {
+			return s.combineParallel(ctx, inputs, out)
+		}
+		if i == len(plan)-1 {
+			return s.combine(ctx, inputs, newStreamWriter(out, size, s.codec, s.p), size)
+		}
+		w := s.newRunSet(size)
+		if err := s.combine(ctx, inputs, w, size); err != nil {
+			return err
+		}
+		s.nextID++
+		runs = append(runs, w.runs...)
+		err := s.checkpoint(func(m *manifest) {
+			m.remove(runIDs(inputs))
+			m.add(w.runs[0].id, w.runs[0].size)
+			m.Passes++
+		})
+		if err != nil {
+			return err
+		}
+		for _, r := range inputs {
+			r.remove()
+		}
+	}
+	return nil
+}
+
+// combine merges runs into w with a single loser tree
+func (s *sorter[T]) combine(ctx context.Context, runs []run[T], w RunWriter[T], size int) error {
+	src, err := openRuns(runs, size, s.codec)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if err := NewCombiner(len(runs), src, s.less, w).KMerge(ctx); err != nil {
+		return err
+	}
+	return src.err
+}
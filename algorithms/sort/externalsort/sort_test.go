@@ -0,0 +1,203 @@
+package externalsort_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/man-fish/goalgorithms/algorithms/sort/externalsort"
+)
+
+func randomInput(n, max int) ([]int, string) {
+	keys := make([]int, n)
+	var b strings.Builder
+	for i := range keys {
+		keys[i] = rand.Intn(max) - max/2
+		fmt.Fprintf(&b, "%d\n", keys[i])
+	}
+	return keys, b.String()
+}
+
+func parseOutput(t *testing.T, out string) []int {
+	var keys []int
+	for _, f := range strings.Fields(out) {
+		k, err := strconv.Atoi(f)
+		if err != nil {
+			t.Fatalf("bad output %q: %v", f, err)
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func checkSorted(t *testing.T, keys, got []int) {
+	want := append([]int(nil), keys...)
+	sort.Ints(want)
+	if len(got) != len(want) {
+		t.Fatalf("sorted %d keys, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("at %d wanted %v but get %v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	for _, n := range []int{0, 1, 7, 1000, 20000} {
+		keys, in := randomInput(n, 1000)
+		var out bytes.Buffer
+		// a tiny budget forces many runs
+		opts := externalsort.Options{TempDir: t.TempDir(), MemoryBudget: 10 << 10}
+		if err := externalsort.Sort(context.Background(), strings.NewReader(in), &out, opts); err != nil {
+			t.Fatalf("sort %d keys: %v", n, err)
+		}
+		checkSorted(t, keys, parseOutput(t, out.String()))
+	}
+}
+
+func TestSortBadInput(t *testing.T) {
+	var out bytes.Buffer
+	err := externalsort.Sort(context.Background(), strings.NewReader("1 2 x 3"), &out, externalsort.Options{TempDir: t.TempDir()})
+	if err == nil {
+		t.Errorf("wanted an error for a non integer key")
+	}
+}
+
+func TestSortFullDomain(t *testing.T) {
+	keys := []int{9999, math.MaxInt32, math.MaxInt64, math.MinInt64, 9999, 0, -1, math.MaxInt32}
+	var in strings.Builder
+	for i := 0; i < 50; i++ {
+		for _, k := range keys {
+			fmt.Fprintf(&in, "%d ", k)
+		}
+	}
+	all := parseOutput(t, in.String())
+	var out bytes.Buffer
+	opts := externalsort.Options{TempDir: t.TempDir(), MemoryBudget: 8<<10 + 10*24}
+	if err := externalsort.Sort(context.Background(), strings.NewReader(in.String()), &out, opts); err != nil {
+		t.Fatal(err)
+	}
+	checkSorted(t, all, parseOutput(t, out.String()))
+}
+
+func TestSortFanIn(t *testing.T) {
+	keys, in := randomInput(20000, 1<<30)
+	for _, fanIn := range []int{2, 3, 16} {
+		var out bytes.Buffer
+		opts := externalsort.Options{TempDir: t.TempDir(), MemoryBudget: 9 << 10, FanIn: fanIn}
+		if err := externalsort.Sort(context.Background(), strings.NewReader(in), &out, opts); err != nil {
+			t.Fatalf("fan-in %d: %v", fanIn, err)
+		}
+		checkSorted(t, keys, parseOutput(t, out.String()))
+	}
+}
+
+func TestSortWorkers(t *testing.T) {
+	keys, in := randomInput(30000, 1<<20)
+	for _, workers := range []int{2, 4, 8} {
+		var out bytes.Buffer
+		opts := externalsort.Options{TempDir: t.TempDir(), MemoryBudget: 64 << 10, FanIn: 8, Workers: workers}
+		if err := externalsort.Sort(context.Background(), strings.NewReader(in), &out, opts); err != nil {
+			t.Fatalf("%d workers: %v", workers, err)
+		}
+		checkSorted(t, keys, parseOutput(t, out.String()))
+	}
+}
+
+func TestSortWorkersFile(t *testing.T) {
+	// a file is written at the offsets of the key ranges, after what it holds
+	keys, in := randomInput(30000, 1<<20)
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.WriteString("head\n")
+	opts := externalsort.Options{TempDir: t.TempDir(), MemoryBudget: 64 << 10, FanIn: 8, Workers: 4}
+	if err := externalsort.Sort(context.Background(), strings.NewReader(in), f, opts); err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("tail\n")
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, head := strings.CutPrefix(string(b), "head\n")
+	out, tail := strings.CutSuffix(out, "tail\n")
+	if !head || !tail {
+		t.Fatalf("wanted the output between head and tail but get %.40q...", b)
+	}
+	checkSorted(t, keys, parseOutput(t, out))
+}
+
+func TestSortWorkersFewKeys(t *testing.T) {
+	// many duplicates leave some key ranges empty
+	keys, in := randomInput(20000, 3)
+	var out bytes.Buffer
+	opts := externalsort.Options{TempDir: t.TempDir(), MemoryBudget: 32 << 10, Workers: 6}
+	if err := externalsort.Sort(context.Background(), strings.NewReader(in), &out, opts); err != nil {
+		t.Fatal(err)
+	}
+	checkSorted(t, keys, parseOutput(t, out.String()))
+}
+
+func TestSortProgress(t *testing.T) {
+	keys, in := randomInput(200000, 1<<20)
+	for _, workers := range []int{1, 4} {
+		var last externalsort.Progress
+		var out bytes.Buffer
+		opts := externalsort.Options{TempDir: t.TempDir(), MemoryBudget: 64 << 10, FanIn: 4, Workers: workers,
+			Progress: func(p externalsort.Progress) {
+				if p.RecordsRead < last.RecordsRead || p.BytesWritten < last.BytesWritten || p.Pass < last.Pass {
+					t.Errorf("progress went back from %+v to %+v", last, p)
+				}
+				if p.Remaining < 0 {
+					t.Errorf("wanted an estimate for an input of known size but get %+v", p)
+				}
+				last = p
+			}}
+		if err := externalsort.Sort(context.Background(), strings.NewReader(in), &out, opts); err != nil {
+			t.Fatal(err)
+		}
+		checkSorted(t, keys, parseOutput(t, out.String()))
+		if last.RecordsRead != int64(len(keys)) || last.BytesRead != int64(len(in)) {
+			t.Errorf("workers %d: wanted %d records and %d bytes read but get %+v", workers, len(keys), len(in), last)
+		}
+		if last.Runs < 2 || last.Passes < 2 || last.Pass != last.Passes || last.Remaining != 0 {
+			t.Errorf("workers %d: wanted all passes done but get %+v", workers, last)
+		}
+		if last.BytesWritten < int64(out.Len()) {
+			t.Errorf("workers %d: wanted at least the %d bytes of the output written but get %+v", workers, out.Len(), last)
+		}
+	}
+}
+
+func TestSortCancel(t *testing.T) {
+	_, in := randomInput(200000, 1<<20)
+	for _, workers := range []int{1, 4} {
+		for _, pass := range []int{0, 1, 2} {
+			ctx, cancel := context.WithCancel(context.Background())
+			opts := externalsort.Options{TempDir: t.TempDir(), MemoryBudget: 64 << 10, FanIn: 4, Workers: workers,
+				Progress: func(p externalsort.Progress) {
+					if p.Runs >= 2 && p.Pass >= pass {
+						cancel()
+					}
+				}}
+			err := externalsort.Sort(ctx, strings.NewReader(in), io.Discard, opts)
+			cancel()
+			if err != context.Canceled {
+				t.Errorf("workers %d pass %d: wanted %v but get %v", workers, pass, context.Canceled, err)
+			}
+		}
+	}
+}
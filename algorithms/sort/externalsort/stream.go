@@ -0,0 +1,196 @@
+package externalsort
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"unsafe"
+)
+
+// errStopped reports the end of a streaming sort whose output was not read to the end
+var errStopped = errors.New("externalsort: streaming sort stopped")
+
+// SortSeq returns the records of seq in the order of less, with the error which
+// stopped the sort if any as the last pair. It holds the records in memory while
+// they fit opts.MemoryBudget and sorts them there, past it the records are spilled
+// to SortFunc with codec, so seq may be much larger than memory. seq is consumed
+// when the result is ranged over, which may stop early.
+func SortSeq[T any](ctx context.Context, seq iter.Seq[T], codec Codec[T], less func(a, b T) bool, opts Options) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var (
+			s     []T
+			used  int64
+			limit = opts.workarea()
+			sp    *spiller[T]
+			err   error
+			zero  T
+		)
+		// seq runs on this goroutine and ends before any record is
+		// yielded, so no producer is left behind if the caller stops
+		for r := range seq {
+			if sp != nil {
+				if err = sp.write(r); err != nil {
+					break
+				}
+				continue
+			}
+			s = append(s, r)
+			used += int64(unsafe.Sizeof(r)) + int64(codec.Size(r))
+			if len(s)%checkEvery == checkEvery-1 {
+				if err = ctx.Err(); err != nil {
+					break
+				}
+			}
+			if used > limit {
+				if sp, err = spill(ctx, s, codec, less, opts); err != nil {
+					break
+				}
+				s = nil
+			}
+		}
+		if sp != nil {
+			sp.sorted(err, yield)
+			return
+		}
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		sortRecords(s, less)
+		for _, r := range s {
+			if !yield(r, nil) {
+				return
+			}
+		}
+	}
+}
+
+// spiller feeds the records past the memory budget through a pipe to
+// SortFunc, which runs on another goroutine and writes to another pipe.
+type spiller[T any] struct {
+	cancel context.CancelFunc
+	codec  Codec[T]
+	pw     *io.PipeWriter
+	w      *bufio.Writer
+	or     *io.PipeReader
+	// done is closed once SortFunc returned err
+	done chan struct{}
+	err  error
+}
+
+// spill starts SortFunc and writes the records of s to it
+func spill[T any](ctx context.Context, s []T, codec Codec[T], less func(a, b T) bool, opts Options) (*spiller[T], error) {
+	ctx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+	or, ow := io.Pipe()
+	sp := &spiller[T]{cancel: cancel, codec: codec, pw: pw, w: bufio.NewWriterSize(pw, minBufferSize), or: or, done: make(chan struct{})}
+	go func() {
+		defer close(sp.done)
+		sp.err = SortFunc(ctx, pr, ow, codec, less, opts)
+		// a failed sort fails the next write of the input
+		pr.CloseWithError(errStopped)
+		ow.CloseWithError(sp.err)
+	}()
+	for _, r := range s {
+		if err := sp.write(r); err != nil {
+			return sp, err
+		}
+	}
+	return sp, nil
+}
+
+// write writes record to the input of the sort
+func (sp *spiller[T]) write(record T) error {
+	return sp.codec.Encode(sp.w, record)
+}
+
+// sorted ends the input of the sort, with err if the input failed, and
+// yields the sorted records, it returns once SortFunc has returned.
+func (sp *spiller[T]) sorted(err error, yield func(T, error) bool) {
+	defer sp.cancel()
+	var zero T
+	if err == nil {
+		err = sp.w.Flush()
+	}
+	if err != nil {
+		sp.pw.CloseWithError(err)
+		sp.or.CloseWithError(errStopped)
+		<-sp.done
+		// a write fails with errStopped if the sort failed first
+		if sp.err != nil {
+			err = sp.err
+		}
+		yield(zero, err)
+		return
+	}
+	sp.pw.Close()
+
+	r := bufio.NewReaderSize(sp.or, minBufferSize)
+	for {
+		record, err := sp.codec.Decode(r)
+		if err == io.EOF {
+			break
+		}
+		if err == nil && yield(record, nil) {
+			continue
+		}
+		// the sort fails on its next write, which the cancel also stops
+		sp.cancel()
+		sp.or.CloseWithError(errStopped)
+		<-sp.done
+		if err != nil {
+			if sp.err != nil {
+				err = sp.err
+			}
+			yield(zero, err)
+		}
+		return
+	}
+	<-sp.done
+	if sp.err != nil {
+		yield(zero, sp.err)
+	}
+}
+
+// SortChannel sorts the records received from in like SortSeq and sends them
+// on the returned channel, which is closed once they are all sent. The error
+// which stopped the sort, if any, is sent on the error channel before it is
+// closed. If ctx is done the sort stops without waiting for in to be closed.
+func SortChannel[T any](ctx context.Context, in <-chan T, codec Codec[T], less func(a, b T) bool, opts Options) (<-chan T, <-chan error) {
+	out := make(chan T)
+	errc := make(chan error, 1)
+	seq := func(yield func(T) bool) {
+		for {
+			select {
+			case r, ok := <-in:
+				if !ok || !yield(r) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+	go func() {
+		defer close(errc)
+		defer close(out)
+		for r, err := range SortSeq(ctx, seq, codec, less, opts) {
+			if err != nil {
+				errc <- err
+				return
+			}
+			select {
+			case out <- r:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return out, errc
+}
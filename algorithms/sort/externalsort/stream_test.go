@@ -0,0 +1,125 @@
+package externalsort_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/man-fish/goalgorithms/algorithms/sort/externalsort"
+)
+
+func lessInt(a, b int) bool { return a < b }
+
+func TestSortSeq(t *testing.T) {
+	for _, budget := range []int64{0, 20 << 10} {
+		for _, n := range []int{0, 1, 1000, 20000} {
+			keys, _ := randomInput(n, 1000)
+			dir := t.TempDir()
+			opts := externalsort.Options{TempDir: dir, MemoryBudget: budget}
+			var got []int
+			for k, err := range externalsort.SortSeq(context.Background(), slices.Values(keys), externalsort.Varint{}, lessInt, opts) {
+				if err != nil {
+					t.Fatalf("sort %d keys in %d bytes: %v", n, budget, err)
+				}
+				got = append(got, k)
+			}
+			checkSorted(t, keys, got)
+			if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+				t.Errorf("sort %d keys in %d bytes: left %d files behind", n, budget, len(entries))
+			}
+		}
+	}
+}
+
+func TestSortSeqStop(t *testing.T) {
+	keys, _ := randomInput(20000, 1000)
+	dir := t.TempDir()
+	opts := externalsort.Options{TempDir: dir, MemoryBudget: 20 << 10}
+	got := 0
+	for _, err := range externalsort.SortSeq(context.Background(), slices.Values(keys), externalsort.Varint{}, lessInt, opts) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got++; got == 10 {
+			break
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("stopped sort left %d files behind", len(entries))
+	}
+}
+
+func TestSortSeqCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	keys, _ := randomInput(20000, 1000)
+	seq := func(yield func(int) bool) {
+		for i, k := range keys {
+			if i == 5000 {
+				cancel()
+			}
+			if !yield(k) {
+				return
+			}
+		}
+	}
+	for _, budget := range []int64{0, 20 << 10} {
+		var last error
+		for _, err := range externalsort.SortSeq(ctx, seq, externalsort.Varint{}, lessInt, externalsort.Options{TempDir: t.TempDir(), MemoryBudget: budget}) {
+			last = err
+		}
+		if !errors.Is(last, context.Canceled) {
+			t.Errorf("budget %d: wanted context.Canceled but get %v", budget, last)
+		}
+	}
+}
+
+func TestSortSeqSortError(t *testing.T) {
+	// the sort fails on its first run, which must stop an endless seq
+	opts := externalsort.Options{TempDir: filepath.Join(t.TempDir(), "missing"), MemoryBudget: 20 << 10}
+	seq := func(yield func(int) bool) {
+		for i := 0; yield(i); i++ {
+		}
+	}
+	var last error
+	for _, err := range externalsort.SortSeq(context.Background(), seq, externalsort.Varint{}, lessInt, opts) {
+		last = err
+	}
+	if last == nil || errors.Is(last, io.ErrClosedPipe) {
+		t.Errorf("wanted the error of the sort but get %v", last)
+	}
+}
+
+func TestSortChannel(t *testing.T) {
+	keys, _ := randomInput(20000, 1000)
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for _, k := range keys {
+			in <- k
+		}
+	}()
+	opts := externalsort.Options{TempDir: t.TempDir(), MemoryBudget: 20 << 10}
+	out, errc := externalsort.SortChannel(context.Background(), in, externalsort.Varint{}, lessInt, opts)
+	var got []int
+	for k := range out {
+		got = append(got, k)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	checkSorted(t, keys, got)
+
+	// a done context stops the sort while in is still open
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out, errc = externalsort.SortChannel(ctx, make(chan int), externalsort.Varint{}, lessInt, opts)
+	for range out {
+	}
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("wanted context.Canceled but get %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package sort
+
+import "math"
+
+const (
+	// flashClassRatio is the number of classes FlashSort makes for each element
+	flashClassRatio = 0.43
+	// flashInsertionMax is the largest class FlashSort sorts by insertion
+	flashInsertionMax = 32
+)
+
+// FlashSort is a O(n) unstable sorting algorithm for uniformly distributed
+// floats, it counts the elements of 0.43*n classes which split the range
+// of s evenly, moves each element into its class in place following the
+// cycles of the permutation and then sorts the classes. The NaNs go first
+// like with slices.Sort, if the range of s is infinite it sorts with
+// PdqSort instead.
+func FlashSort(s []float64) {
+	s = nanFirst(s)
+	lo, hi, ok := floatRange(s)
+	if !ok {
+		return
+	}
+	if math.IsInf(hi-lo, 0) {
+		PdqSortOrdered(s)
+		return
+	}
+	m := min(max(int(flashClassRatio*float64(len(s))), 2), MaxBuckets)
+
+	// start[k] is the start of class k, start[m] is len(s)
+	start := make([]int, m+1)
+	for _, v := range s {
+		start[bucketOf(v, lo, hi, m)+1]++
+	}
+	for k := 1; k <= m; k++ {
+		start[k] += start[k-1]
+	}
+	// next[k] is the first element of class k which may not belong to it
+	next := append([]int(nil), start[:m]...)
+	for k := 0; k < m; k++ {
+		for next[k] < start[k+1] {
+			// move the element out of place to its class and take the one
+			// there in its stead, until one belongs to class k
+			v := s[next[k]]
+			for c := bucketOf(v, lo, hi, m); c != k; c = bucketOf(v, lo, hi, m) {
+				s[next[c]], v = v, s[next[c]]
+				next[c]++
+			}
+			s[next[k]] = v
+			next[k]++
+		}
+	}
+	// a skewed input makes large classes, which insertion would sort in O(n^2)
+	for k := 0; k < m; k++ {
+		class := s[start[k]:start[k+1]]
+		if len(class) <= flashInsertionMax {
+			for i := 1; i < len(class); i++ {
+				for j := i; j > 0 && class[j] < class[j-1]; j-- {
+					class[j], class[j-1] = class[j-1], class[j]
+				}
+			}
+		} else {
+			PdqSortOrdered(class)
+		}
+	}
+}
+
+/*
+Complexity of flash sort：
+	* Best: 	O(n)
+	* Average: 	O(n)
+	* Worst: 	O(nlog(n))
+	* Memory: 	O(m)
+	* Stable: 	No
+	* Wiki: 	https://en.wikipedia.org/wiki/Flashsort
+Shortcome from wiki:
+	Flashsort is a distribution sorting algorithm showing linear
+	computational complexity O(n) for uniformly distributed data sets
+	and relatively little additional memory requirement. The basic idea
+	is that the elements are classified into m buckets by a linear
+	interpolation of their value, then permuted in place to their
+	buckets, which are finally sorted by insertion. Its worst case is
+	O(n^2) when most elements fall in one bucket, which is avoided here
+	by sorting the large buckets with a O(nlog(n)) algorithm.
+*/
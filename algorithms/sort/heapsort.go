@@ -1,10 +1,51 @@
 package sort
 
-import "github.com/man-fish/goalgorithms/datastructures/heap"
+import "cmp"
 
-// HeapSort is a O(nlog(n)) stable sorting algorithm
-func HeapSort(data []int) []int {
-	return heap.Sort(data)
+// HeapSort is a O(nlog(n)) unstable sorting algorithm
+func HeapSort(data Sortable) {
+	heapSort(data, 0, data.Len())
+}
+
+// heapSort sorts data[a:b]
+func heapSort(data Sortable, a, b int) {
+	n := b - a
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDown(data, a, i, n)
+	}
+	for i := n - 1; i > 0; i-- {
+		data.Swap(a, a+i)
+		siftDown(data, a, 0, i)
+	}
+}
+
+// siftDown moves the element at root down the max heap data[a:a+n],
+// root and its children are counted from a.
+func siftDown(data Sortable, a, root, n int) {
+	for {
+		child := 2*root + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && data.Less(a+child, a+child+1) {
+			child++
+		}
+		if !data.Less(a+root, a+child) {
+			return
+		}
+		data.Swap(a+root, a+child)
+		root = child
+	}
+}
+
+// HeapSortSlice sorts s in the order of compare with HeapSort
+func HeapSortSlice[T any](s []T, compare func(a, b T) int) {
+	HeapSort(&funcSlice[T]{s: s, compare: compare})
+}
+
+// HeapSortOrdered sorts s in increasing order with HeapSort
+func HeapSortOrdered[T cmp.Ordered](s []T) {
+	HeapSort(orderedSlice[T](s))
 }
 
 /*
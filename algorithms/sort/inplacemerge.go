@@ -0,0 +1,149 @@
+package sort
+
+import "cmp"
+
+// inPlaceBlock is the length of the blocks InPlaceMergeSort sorts by insertion
+const inPlaceBlock = 20
+
+// InPlaceMergeSort is a O(nlog(n)^2) stable sorting algorithm which allocates
+// no memory, the recursion of its merges takes O(log(n)) stack. It sorts
+// blocks of data by insertion and then merges them bottom up with SymMerge,
+// which rotates the parts of two sorted runs into place instead of copying
+// them out. It makes more swaps than MergeSort, so it is slower unless the
+// O(n) indexes of MergeSort do not fit in memory.
+func InPlaceMergeSort(data Sortable) {
+	n := data.Len()
+	a, b := 0, inPlaceBlock
+	for ; b <= n; a, b = b, b+inPlaceBlock {
+		insertionSort(data, a, b)
+	}
+	insertionSort(data, a, n)
+	for size := inPlaceBlock; size < n; size *= 2 {
+		a, b = 0, 2*size
+		for ; b <= n; a, b = b, b+2*size {
+			symMerge(data, a, a+size, b)
+		}
+		if m := a + size; m < n {
+			symMerge(data, a, m, n)
+		}
+	}
+}
+
+// InPlaceMergeSortSlice sorts s in the order of compare with InPlaceMergeSort
+func InPlaceMergeSortSlice[T any](s []T, compare func(a, b T) int) {
+	InPlaceMergeSort(&funcSlice[T]{s: s, compare: compare})
+}
+
+// InPlaceMergeSortOrdered sorts s in increasing order with InPlaceMergeSort
+func InPlaceMergeSortOrdered[T cmp.Ordered](s []T) {
+	InPlaceMergeSort(orderedSlice[T](s))
+}
+
+// symMerge merges the sorted data[a:m] and data[m:b] in place with the SymMerge
+// algorithm of Kim and Kutzner. It finds the longest suffix of data[a:m] and
+// prefix of data[m:b] of the same length, placed symmetrically around the middle
+// of data[a:b], whose elements all go after each other, swaps them by rotation
+// and merges each side of the middle.
+func symMerge(data Sortable, a, m, b int) {
+	// a single element is inserted by binary search, after
+	// its equals on the left and before those on the right
+	if m-a == 1 {
+		i, j := m, b
+		for i < j {
+			h := int(uint(i+j) >> 1)
+			if data.Less(h, a) {
+				i = h + 1
+			} else {
+				j = h
+			}
+		}
+		for k := a; k < i-1; k++ {
+			data.Swap(k, k+1)
+		}
+		return
+	}
+	if b-m == 1 {
+		i, j := a, m
+		for i < j {
+			h := int(uint(i+j) >> 1)
+			if !data.Less(m, h) {
+				i = h + 1
+			} else {
+				j = h
+			}
+		}
+		for k := m; k > i; k-- {
+			data.Swap(k, k-1)
+		}
+		return
+	}
+
+	mid := int(uint(a+b) >> 1)
+	n := mid + m
+	var start, r int
+	if m > mid {
+		start, r = n-b, mid
+	} else {
+		start, r = a, m
+	}
+	// data[start:m] and data[m:n-start] are swapped
+	p := n - 1
+	for start < r {
+		c := int(uint(start+r) >> 1)
+		if !data.Less(p-c, c) {
+			start = c + 1
+		} else {
+			r = c
+		}
+	}
+	end := n - start
+	if start < m && m < end {
+		rotate(data, start, m, end)
+	}
+	if a < start && start < mid {
+		symMerge(data, a, start, mid)
+	}
+	if mid < end && end < b {
+		symMerge(data, mid, end, b)
+	}
+}
+
+// rotate swaps data[a:m] and data[m:b] by swapping
+// blocks of the length of the shorter one.
+func rotate(data Sortable, a, m, b int) {
+	i, j := m-a, b-m
+	for i != j {
+		if i > j {
+			swapRange(data, m-i, m, j)
+			i -= j
+		} else {
+			swapRange(data, m-i, m+j-i, i)
+			j -= i
+		}
+	}
+	swapRange(data, m-i, m, i)
+}
+
+// swapRange swaps the n elements from a with the n elements from b
+func swapRange(data Sortable, a, b, n int) {
+	for i := 0; i < n; i++ {
+		data.Swap(a+i, b+i)
+	}
+}
+
+/*
+Complexity of in-place merge sort：
+	* Best: 	O(n)
+	* Average: 	O(nlog(n)^2)
+	* Worst: 	O(nlog(n)^2)
+	* Memory: 	O(log(n))
+	* Stable: 	Yes
+	* Wiki: 	https://en.wikipedia.org/wiki/Merge_sort#Variants
+Shortcome from wiki:
+	Variants of merge sort are primarily concerned with reducing the space
+	complexity and the cost of copying. A simple alternative for reducing
+	the space overhead is merging in place, for which Kim and Kutzner's
+	SymMerge uses O(nlog(n)^2) time: it splits both runs so that the
+	middle parts are swapped by a rotation and merges the two sides
+	recursively, needing no buffer but the recursion.
+*/
@@ -0,0 +1,53 @@
+package sort_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+)
+
+func TestInPlaceMergeSort(t *testing.T) {
+	for _, n := range []int{19, 20, 21, 41, 1000, 100000} {
+		s := make([]pair, n)
+		for i := range s {
+			s[i] = pair{key: rand.Intn(n/10 + 1), pos: i}
+		}
+		want := slices.Clone(s)
+		slices.SortStableFunc(want, comparePairs)
+		isort.InPlaceMergeSortSlice(s, comparePairs)
+		if !slices.Equal(s, want) {
+			t.Fatalf("%d pairs: not stable", n)
+		}
+	}
+	for name, gen := range killers {
+		n := 10000
+		d := &countingInts{t: t, data: gen(n), max: 4 * n * 14 * 14}
+		isort.InPlaceMergeSort(d)
+		if !slices.IsSorted(d.data) {
+			t.Errorf("%s of %d: not sorted", name, n)
+		}
+	}
+	var data isort.Sortable = isort.RandomArray(1000, 100)
+	if a := testing.AllocsPerRun(1, func() { isort.InPlaceMergeSort(data) }); a != 0 {
+		t.Errorf("wanted no allocation but get %v", a)
+	}
+}
+
+// The stable sorts report their allocations, the in-place sort
+// only allocates the Sortable of the Ordered form.
+func BenchmarkInPlaceMergeSort(b *testing.B) {
+	b.ReportAllocs()
+	benchmarkInts(b, isort.InPlaceMergeSortOrdered[int])
+}
+
+func BenchmarkMergeSortAllocs(b *testing.B) {
+	b.ReportAllocs()
+	benchmarkInts(b, isort.MergeSortOrdered[int])
+}
+
+func BenchmarkTimSortAllocs(b *testing.B) {
+	b.ReportAllocs()
+	benchmarkInts(b, isort.TimSortOrdered[int])
+}
@@ -1,14 +1,31 @@
 package sort
 
+import "cmp"
+
 // InsertionSort is a O(n^2) stable sorting algorithm
 func InsertionSort(data Sortable) {
-	for i := 1; i < data.Len(); i++ {
-		for j := i; j > 0 && data.Less(j, j-1); j-- {
+	insertionSort(data, 0, data.Len())
+}
+
+// insertionSort sorts data[a:b]
+func insertionSort(data Sortable, a, b int) {
+	for i := a + 1; i < b; i++ {
+		for j := i; j > a && data.Less(j, j-1); j-- {
 			data.Swap(j, j-1)
 		}
 	}
 }
 
+// InsertionSortSlice sorts s in the order of compare with InsertionSort
+func InsertionSortSlice[T any](s []T, compare func(a, b T) int) {
+	InsertionSort(&funcSlice[T]{s: s, compare: compare})
+}
+
+// InsertionSortOrdered sorts s in increasing order with InsertionSort
+func InsertionSortOrdered[T cmp.Ordered](s []T) {
+	InsertionSort(orderedSlice[T](s))
+}
+
 /*
 Complexity of insertion sort：
 	* Best: 	O(n)
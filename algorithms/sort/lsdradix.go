@@ -0,0 +1,126 @@
+package sort
+
+import "math"
+
+// RadixSortUint64 is a O(n) stable sorting algorithm, it sorts s with a
+// counting sort on each byte from the lowest and skips the bytes all
+// elements share, so small keys take fewer passes.
+func RadixSortUint64(s []uint64) {
+	lsdRadixSort(s, make([]uint64, len(s)))
+}
+
+// RadixSortInt64 sorts s like RadixSortUint64, flipping the sign
+// bit maps the negative numbers before the positive ones.
+func RadixSortInt64(s []int64) {
+	keys := make([]uint64, len(s))
+	for i, v := range s {
+		keys[i] = uint64(v) ^ 1<<63
+	}
+	lsdRadixSort(keys, make([]uint64, len(s)))
+	for i, k := range keys {
+		s[i] = int64(k ^ 1<<63)
+	}
+}
+
+// RadixSortFloat64 sorts s like RadixSortUint64 in the total order of
+// IEEE-754: -NaN, -Inf, negative numbers, -0, +0, positive numbers, +Inf, NaN.
+func RadixSortFloat64(s []float64) {
+	keys := make([]uint64, len(s))
+	for i, v := range s {
+		keys[i] = floatKey(v)
+	}
+	lsdRadixSort(keys, make([]uint64, len(s)))
+	for i, k := range keys {
+		if k>>63 == 1 {
+			k &^= 1 << 63
+		} else {
+			k = ^k
+		}
+		s[i] = math.Float64frombits(k)
+	}
+}
+
+// floatKey maps f to a uint64 in the total order of IEEE-754, the bits of a
+// positive float grow with it so it only needs the sign bit set, those of a
+// negative float grow as it shrinks so they are all flipped.
+func floatKey(f float64) uint64 {
+	b := math.Float64bits(f)
+	if b>>63 == 1 {
+		return ^b
+	}
+	return b | 1<<63
+}
+
+// lsdRadixSort sorts keys with a counting sort on each byte
+// from the lowest, buf is a scratch slice of the same length.
+func lsdRadixSort(keys, buf []uint64) {
+	if len(keys) < 2 {
+		return
+	}
+	var counts [8][256]int
+	for _, k := range keys {
+		for b := range counts {
+			counts[b][byte(k>>(8*b))]++
+		}
+	}
+	src, dst := keys, buf
+	for b := range counts {
+		count := &counts[b]
+		if count[byte(src[0]>>(8*b))] == len(src) {
+			continue
+		}
+		offset := 0
+		for c, n := range count {
+			count[c], offset = offset, offset+n
+		}
+		for _, k := range src {
+			c := byte(k >> (8 * b))
+			dst[count[c]] = k
+			count[c]++
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &keys[0] {
+		copy(keys, src)
+	}
+}
+
+// RadixSortBy sorts records by key with RadixSortUint64, records with
+// equal keys keep their order. key is called once for each record.
+func RadixSortBy[T any](records []T, key func(T) uint64) {
+	if len(records) < 2 {
+		return
+	}
+	keys := make([]uint64, len(records))
+	for i, r := range records {
+		keys[i] = key(r)
+	}
+	var counts [8][256]int
+	for _, k := range keys {
+		for b := range counts {
+			counts[b][byte(k>>(8*b))]++
+		}
+	}
+	src, srcKeys := records, keys
+	dst, dstKeys := make([]T, len(records)), make([]uint64, len(records))
+	for b := range counts {
+		count := &counts[b]
+		if count[byte(srcKeys[0]>>(8*b))] == len(srcKeys) {
+			continue
+		}
+		offset := 0
+		for c, n := range count {
+			count[c], offset = offset, offset+n
+		}
+		for i, k := range srcKeys {
+			c := byte(k >> (8 * b))
+			dst[count[c]], dstKeys[count[c]] = src[i], k
+			count[c]++
+		}
+		src, dst = dst, src
+		srcKeys, dstKeys = dstKeys, srcKeys
+	}
+	if &src[0] != &records[0] {
+		copy(records, src)
+	}
+}
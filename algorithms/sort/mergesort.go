@@ -1,53 +1,68 @@
 package sort
 
-// MergeSort is a O(nlog(n)) stable sorting algorithm
-func MergeSort(data []int) {
-	mergeSort(data, make([]int, len(data)), 0, len(data)-1)
+import "cmp"
+
+// MergeSort is a O(nlog(n)) stable sorting algorithm, it merge sorts
+// the indexes of data and then moves each element to its place once.
+func MergeSort(data Sortable) {
+	idx := make([]int, data.Len())
+	for i := range idx {
+		idx[i] = i
+	}
+	mergeSort(data, idx, make([]int, len(idx)), 0, len(idx)-1)
+	permute(data, idx)
 }
 
-func mergeSort(data, temp []int, start, end int) {
+func mergeSort(data Sortable, idx, temp []int, start, end int) {
 	if start < end {
 		mid := int(uint(start+end) >> 1)
-		mergeSort(data, temp, start, mid)
-		mergeSort(data, temp, mid+1, end)
-		merge(data, temp, start, mid, end)
+		mergeSort(data, idx, temp, start, mid)
+		mergeSort(data, idx, temp, mid+1, end)
+		merge(data, idx, temp, start, mid, end)
 	}
 }
 
-func merge(data, temp []int, start, mid, end int) {
+// merge merges the sorted idx[start..mid] and idx[mid+1..end],
+// the left index goes first unless its element is greater.
+func merge(data Sortable, idx, temp []int, start, mid, end int) {
 	i := start
 	j := mid + 1
 	t := 0
 	for i <= mid && j <= end {
-		if data[i] < data[j] {
-			temp[t] = data[i]
-			i++
+		if data.Less(idx[j], idx[i]) {
+			temp[t] = idx[j]
+			j++
 			t++
 		} else {
-			temp[t] = data[j]
-			j++
+			temp[t] = idx[i]
+			i++
 			t++
 		}
 	}
 
 	for i <= mid {
-		temp[t] = data[i]
+		temp[t] = idx[i]
 		i++
 		t++
 	}
 
 	for j <= end {
-		temp[t] = data[j]
+		temp[t] = idx[j]
 		j++
 		t++
 	}
 
-	t = 0
-	for start <= end {
-		data[start] = temp[t]
-		t++
-		start++
-	}
+	copy(idx[start:end+1], temp[:t])
+}
+
+// MergeSortSlice sorts s in the order of compare with MergeSort
+func MergeSortSlice[T any](s []T, compare func(a, b T) int) {
+	MergeSort(&funcSlice[T]{s: s, compare: compare})
+}
+
+// MergeSortOrdered sorts s in increasing order with MergeSort
+func MergeSortOrdered[T cmp.Ordered](s []T) {
+	MergeSort(orderedSlice[T](s))
 }
 
 /*
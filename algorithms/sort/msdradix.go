@@ -0,0 +1,111 @@
+package sort
+
+// msdInsertionMax is the longest part RadixSortStrings sorts by insertion
+const msdInsertionMax = 32
+
+// RadixSortStrings is a O(n*k) stable sorting algorithm for strings, k being
+// the length of their common prefixes. It distributes s by the first byte and
+// then sorts each bucket by the next byte, the strings which end come first.
+func RadixSortStrings(s []string) {
+	msdRadixSort(s, make([]string, len(s)))
+}
+
+// RadixSortBytes sorts s like RadixSortStrings in the order of bytes.Compare
+func RadixSortBytes(s [][]byte) {
+	msdRadixSort(s, make([][]byte, len(s)))
+}
+
+// msdPart is the part [lo, hi) of the input whose elements share their first d bytes
+type msdPart struct {
+	lo, hi, d int
+}
+
+// msdRadixSort sorts s, buf is a scratch slice of the same length. The parts
+// left to sort are kept on a stack instead of recursing, the depth of the
+// recursion would be the length of the longest common prefix.
+func msdRadixSort[S ~string | ~[]byte](s, buf []S) {
+	// bucket 0 holds the elements which end at d, bucket c+1 those with byte c at d
+	var count [258]int
+	stack := []msdPart{{0, len(s), 0}}
+next:
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		part, d := s[p.lo:p.hi], p.d
+		if len(part) <= msdInsertionMax {
+			for i := 1; i < len(part); i++ {
+				for j := i; j > 0 && lessFrom(part[j], part[j-1], d); j-- {
+					part[j], part[j-1] = part[j-1], part[j]
+				}
+			}
+			continue
+		}
+		for {
+			count = [258]int{}
+			for _, x := range part {
+				count[byteAt(x, d)+2]++
+			}
+			// a byte which every element shares moves nothing
+			c := byteAt(part[0], d) + 2
+			if count[c] < len(part) {
+				break
+			}
+			if c == 1 {
+				// every element ends at d, they are all equal
+				continue next
+			}
+			d++
+		}
+		for c := 1; c < len(count); c++ {
+			count[c] += count[c-1]
+		}
+		tmp := buf[p.lo:p.hi]
+		for _, x := range part {
+			c := byteAt(x, d) + 1
+			tmp[count[c]] = x
+			count[c]++
+		}
+		copy(part, tmp)
+		// count[c] is now the end of bucket c
+		for c := 1; c < len(count)-1; c++ {
+			if lo, hi := count[c-1], count[c]; hi-lo > 1 {
+				stack = append(stack, msdPart{p.lo + lo, p.lo + hi, d + 1})
+			}
+		}
+	}
+}
+
+// byteAt returns the byte of x at d, -1 if x ends before
+func byteAt[S ~string | ~[]byte](x S, d int) int {
+	if d < len(x) {
+		return int(x[d])
+	}
+	return -1
+}
+
+// lessFrom compares a and b from their byte at d
+func lessFrom[S ~string | ~[]byte](a, b S, d int) bool {
+	for ; d < len(a) && d < len(b); d++ {
+		if a[d] != b[d] {
+			return a[d] < b[d]
+		}
+	}
+	return len(a) < len(b)
+}
+
+/*
+Complexity of msd radix sort：
+	* Best: 	O(n)
+	* Average: 	O(n*k)
+	* Worst: 	O(n*k)
+	* Memory: 	O(n)
+	* Stable: 	Yes
+	* Wiki: 	https://en.wikipedia.org/wiki/Radix_sort#Most_significant_digit
+Shortcome from wiki:
+	A recursively subdividing MSD radix sort algorithm works as follows:
+	the input is split into buckets by the value of the leftmost digit,
+	then each bucket is sorted recursively by the next digit. Keys which
+	run out of digits go before the longer ones, so strings sort in
+	lexicographic order and the sort may stop as soon as every bucket
+	holds a single key.
+*/
@@ -0,0 +1,148 @@
+package sort
+
+import "cmp"
+
+// MaxNetwork is the longest slice SortSmall sorts with a sorting network
+const MaxNetwork = 32
+
+// comparator puts the lesser of the elements at its two indexes first
+type comparator [2]uint8
+
+// networks[n] sorts n elements, it is the one with fewer comparators
+// of the Bose-Nelson network and Batcher's odd-even merge network.
+var networks = makeNetworks()
+
+func makeNetworks() [MaxNetwork + 1][]comparator {
+	var nets [MaxNetwork + 1][]comparator
+	for n := 2; n <= MaxNetwork; n++ {
+		nets[n] = boseNelson(n)
+		if oem := oddEvenMerge(n); len(oem) < len(nets[n]) {
+			nets[n] = oem
+		}
+	}
+	return nets
+}
+
+// boseNelson returns the network of Bose and Nelson, it sorts both
+// halves and merges them splitting each merge in three smaller ones.
+func boseNelson(n int) []comparator {
+	var net []comparator
+	var sort func(i, n int)
+	var merge func(i, x, j, y int)
+	sort = func(i, n int) {
+		if n < 2 {
+			return
+		}
+		m := n / 2
+		sort(i, m)
+		sort(i+m, n-m)
+		merge(i, m, i+m, n-m)
+	}
+	// merge merges the sorted x elements from i and y elements from j
+	merge = func(i, x, j, y int) {
+		switch {
+		case x == 1 && y == 1:
+			net = append(net, comparator{uint8(i), uint8(j)})
+		case x == 1 && y == 2:
+			net = append(net, comparator{uint8(i), uint8(j + 1)}, comparator{uint8(i), uint8(j)})
+		case x == 2 && y == 1:
+			net = append(net, comparator{uint8(i), uint8(j)}, comparator{uint8(i + 1), uint8(j)})
+		default:
+			a := x / 2
+			b := (y + 1) / 2
+			if x%2 == 1 {
+				b = y / 2
+			}
+			merge(i, a, j, b)
+			merge(i+a, x-a, j+b, y-b)
+			merge(i+a, x-a, j, b)
+		}
+	}
+	sort(0, n)
+	return net
+}
+
+// oddEvenMerge returns Batcher's odd-even merge network of the next
+// power of two without the comparators of the elements past n.
+func oddEvenMerge(n int) []comparator {
+	var net []comparator
+	for p := 1; p < n; p *= 2 {
+		for k := p; k >= 1; k /= 2 {
+			for j := k % p; j+k < n; j += 2 * k {
+				for i := 0; i < k && i+j+k < n; i++ {
+					if (i+j)/(2*p) == (i+j+k)/(2*p) {
+						net = append(net, comparator{uint8(i + j), uint8(i + j + k)})
+					}
+				}
+			}
+		}
+	}
+	return net
+}
+
+// SortSmall is a O(1) unstable sorting algorithm for at most MaxNetwork
+// elements, it runs data through a sorting network, whose comparisons do not
+// depend on the order of data, and it sorts longer data with PdqSort.
+func SortSmall(data Sortable) {
+	n := data.Len()
+	if n > MaxNetwork {
+		PdqSort(data)
+		return
+	}
+	for _, c := range networks[n] {
+		if i, j := int(c[0]), int(c[1]); data.Less(j, i) {
+			data.Swap(i, j)
+		}
+	}
+}
+
+// SortSmallSlice sorts s in the order of compare with SortSmall
+func SortSmallSlice[T any](s []T, compare func(a, b T) int) {
+	if len(s) > MaxNetwork {
+		PdqSortSlice(s, compare)
+		return
+	}
+	for _, c := range networks[len(s)] {
+		a, b := s[c[0]], s[c[1]]
+		if compare(b, a) < 0 {
+			a, b = b, a
+		}
+		s[c[0]], s[c[1]] = a, b
+	}
+}
+
+// SortSmallOrdered sorts s in increasing order with SortSmall, both
+// elements of a comparator are always written back so that the
+// compiler may select them without a branch.
+func SortSmallOrdered[T cmp.Ordered](s []T) {
+	if len(s) > MaxNetwork {
+		PdqSortOrdered(s)
+		return
+	}
+	for _, c := range networks[len(s)] {
+		a, b := s[c[0]], s[c[1]]
+		if cmp.Less(b, a) {
+			a, b = b, a
+		}
+		s[c[0]], s[c[1]] = a, b
+	}
+}
+
+/*
+Complexity of sorting network：
+	* Best: 	O(c)
+	* Average: 	O(c)
+	* Worst: 	O(c)
+	* Memory: 	O(1)
+	* Stable: 	No
+	* Wiki: 	https://en.wikipedia.org/wiki/Sorting_network
+Shortcome from wiki:
+	Sorting networks are abstract devices built up of a fixed number of
+	"wires", carrying values, and comparator modules that connect pairs of
+	wires, swapping the values on the wires if they are not in a desired
+	order. Unlike comparison sorts, they perform the same sequence of
+	comparisons regardless of the input, which makes them suited to small
+	fixed sizes in hardware and in branch-free software. Batcher's odd-even
+	mergesort and the network of Bose and Nelson use O(nlog(n)^2)
+	comparators, c being that number.
+*/
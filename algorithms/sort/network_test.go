@@ -0,0 +1,132 @@
+package sort_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+)
+
+func TestSortSmall(t *testing.T) {
+	for n := 0; n <= isort.MaxNetwork+2; n++ {
+		for k := 0; k < 200; k++ {
+			src := []int(isort.RandomArray(n, n/2+2))
+			s := slices.Clone(src)
+			isort.SortSmallOrdered(s)
+			if !slices.IsSorted(s) {
+				t.Fatalf("%d elements: %v sorts to %v", n, src, s)
+			}
+			p := make([]pair, n)
+			for i := range p {
+				p[i] = pair{key: src[i], pos: i}
+			}
+			isort.SortSmallSlice(p, comparePairs)
+			if !slices.IsSortedFunc(p, comparePairs) {
+				t.Fatalf("%d pairs: not sorted", n)
+			}
+			a := isort.IntSlice(slices.Clone(src))
+			isort.SortSmall(a)
+			if !slices.Equal(a, s) {
+				t.Fatalf("%d elements: wanted %v but get %v", n, s, a)
+			}
+		}
+	}
+}
+
+// By the 0-1 principle a network sorts all inputs if it sorts those of zeros
+// and ones. Each bit of a wire holds one of the 2^n inputs, a comparator puts
+// the and of its wires on the first and the or on the second.
+func TestSortSmallZeroOne(t *testing.T) {
+	maxN := 22
+	if testing.Short() {
+		maxN = 14
+	}
+	for n := 2; n <= maxN; n++ {
+		inputs := 1 << n
+		words := (inputs + 63) / 64
+		wires := make([][]uint64, n)
+		for w := range wires {
+			wires[w] = make([]uint64, words)
+			for x := 0; x < inputs; x++ {
+				wires[w][x/64] |= uint64(x>>w&1) << (x % 64)
+			}
+		}
+		// a Sortable over the wires whose swaps apply the comparators
+		net := &zeroOne{wires: wires}
+		isort.SortSmall(net)
+		for w := 1; w < n; w++ {
+			for i := range words {
+				// a one on a wire must be followed by ones
+				if wires[w-1][i]&^wires[w][i] != 0 {
+					t.Fatalf("network of %d does not sort wires %d and %d", n, w-1, w)
+				}
+			}
+		}
+	}
+}
+
+// zeroOne runs a network on all inputs of zeros and ones, Less always
+// reports true so that every comparator swaps, and Swap sorts the wires.
+type zeroOne struct {
+	wires [][]uint64
+}
+
+func (z *zeroOne) Len() int            { return len(z.wires) }
+func (z *zeroOne) Less(i, j int) bool  { return true }
+func (z *zeroOne) Equal(i, j int) bool { return false }
+func (z *zeroOne) Swap(i, j int) {
+	a, b := z.wires[i], z.wires[j]
+	for k := range a {
+		a[k], b[k] = a[k]&b[k], a[k]|b[k]
+	}
+}
+
+func TestBitonicSort(t *testing.T) {
+	for _, opts := range parallelOptions {
+		for _, n := range []int{0, 1, 2, 64, 1 << 14} {
+			s := []int(isort.RandomArray(n, n/4+1))
+			isort.BitonicSortOrdered(s, opts)
+			if !slices.IsSorted(s) {
+				t.Fatalf("%+v of %d: not sorted", opts, n)
+			}
+			p := make([]pair, n)
+			for i := range p {
+				p[i] = pair{key: rand.Intn(10), pos: i}
+			}
+			isort.BitonicSortSlice(p, comparePairs, opts)
+			if !slices.IsSortedFunc(p, comparePairs) {
+				t.Fatalf("%+v of %d pairs: not sorted", opts, n)
+			}
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("wanted a panic for 3 elements")
+		}
+	}()
+	isort.BitonicSortOrdered([]int{3, 1, 2}, isort.ParallelOptions{})
+}
+
+func benchmarkSmall(b *testing.B, sort func(s []int)) {
+	src := []int(isort.RandomArray(1<<16, 1<<16))
+	s := make([]int, 16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		off := i * 16 % len(src)
+		copy(s, src[off:off+16])
+		sort(s)
+	}
+}
+
+func BenchmarkSortSmall16(b *testing.B) {
+	benchmarkSmall(b, isort.SortSmallOrdered[int])
+}
+
+func BenchmarkInsertionSort16(b *testing.B) {
+	benchmarkSmall(b, isort.InsertionSortOrdered[int])
+}
+
+func BenchmarkBitonicSort(b *testing.B) {
+	benchmarkInts(b, func(s []int) { isort.BitonicSortOrdered(s, isort.ParallelOptions{}) })
+}
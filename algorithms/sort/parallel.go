@@ -0,0 +1,224 @@
+package sort
+
+import (
+	"cmp"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// DefaultGrain is the grain used when ParallelOptions.Grain is 0
+const DefaultGrain = 1 << 12
+
+// ParallelOptions configures the parallel sorts
+type ParallelOptions struct {
+	// Grain is the length below which a part is sorted, or two
+	// parts are merged, on the goroutine which holds them.
+	Grain int
+	// Workers bounds the goroutines sorting at the same time,
+	// runtime.GOMAXPROCS(0) if 0 and sequential if 1.
+	Workers int
+}
+
+// forker runs the two halves of a divide and conquer step at
+// the same time while fewer than Workers goroutines are busy.
+type forker struct {
+	grain int
+	// sem holds a token for each goroutine forked, the
+	// goroutine of the caller needs none
+	sem chan struct{}
+}
+
+func newForker(opts ParallelOptions) *forker {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	grain := opts.Grain
+	if grain <= 0 {
+		grain = DefaultGrain
+	}
+	return &forker{grain: max(grain, 2), sem: make(chan struct{}, workers-1)}
+}
+
+// fork runs a on a new goroutine if a worker is free and b
+// on the current one, it returns once both are done.
+func (f *forker) fork(a, b func()) {
+	select {
+	case f.sem <- struct{}{}:
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-f.sem }()
+			a()
+		}()
+		b()
+		wg.Wait()
+	default:
+		a()
+		b()
+	}
+}
+
+// ParallelMergeSort is a O(nlog(n)) stable sorting algorithm, it sorts the
+// halves of data and merges them on goroutines down to parts of opts.Grain.
+// Like MergeSort it sorts the indexes of data and then moves each element to
+// its place once, so Less is called concurrently and must be safe for it.
+func ParallelMergeSort(data Sortable, opts ParallelOptions) {
+	idx := make([]int, data.Len())
+	for i := range idx {
+		idx[i] = i
+	}
+	parallelMergeSort(newForker(opts), idx, make([]int, len(idx)), false, data.Less)
+	permute(data, idx)
+}
+
+// ParallelMergeSortSlice sorts s in the order of compare with ParallelMergeSort
+func ParallelMergeSortSlice[T any](s []T, compare func(a, b T) int, opts ParallelOptions) {
+	less := func(a, b T) bool { return compare(a, b) < 0 }
+	parallelMergeSort(newForker(opts), s, make([]T, len(s)), false, less)
+}
+
+// ParallelMergeSortOrdered sorts s in increasing order with ParallelMergeSort
+func ParallelMergeSortOrdered[T cmp.Ordered](s []T, opts ParallelOptions) {
+	parallelMergeSort(newForker(opts), s, make([]T, len(s)), false, cmp.Less[T])
+}
+
+// parallelMergeSort sorts s into buf if toBuf is set and into s otherwise,
+// the halves are sorted into the other slice so that they merge into the
+// right one without copies.
+func parallelMergeSort[T any](f *forker, s, buf []T, toBuf bool, less func(a, b T) bool) {
+	if len(s) <= f.grain {
+		timSort(s, less)
+		if toBuf {
+			copy(buf, s)
+		}
+		return
+	}
+	mid := len(s) / 2
+	f.fork(func() {
+		parallelMergeSort(f, s[:mid], buf[:mid], !toBuf, less)
+	}, func() {
+		parallelMergeSort(f, s[mid:], buf[mid:], !toBuf, less)
+	})
+	if toBuf {
+		parallelMerge(f, s[:mid], s[mid:], buf, less)
+	} else {
+		parallelMerge(f, buf[:mid], buf[mid:], s, less)
+	}
+}
+
+// parallelMerge merges the sorted a and b into dst, the elements of a go before
+// the equal ones of b. It splits the longer slice at its middle element m and the
+// other one where m goes by binary search, then merges both sides at the same time.
+func parallelMerge[T any](f *forker, a, b, dst []T, less func(a, b T) bool) {
+	if len(a)+len(b) <= f.grain {
+		mergeInto(a, b, dst, less)
+		return
+	}
+	var ma, mb int
+	if len(a) >= len(b) {
+		// the elements of b before a[ma] are less than it
+		ma = len(a) / 2
+		mb = searchFirst(len(b), func(i int) bool { return !less(b[i], a[ma]) })
+		dst[ma+mb] = a[ma]
+		f.fork(func() {
+			parallelMerge(f, a[:ma], b[:mb], dst[:ma+mb], less)
+		}, func() {
+			parallelMerge(f, a[ma+1:], b[mb:], dst[ma+mb+1:], less)
+		})
+		return
+	}
+	// the elements of a before b[mb] are not greater than it
+	mb = len(b) / 2
+	ma = searchFirst(len(a), func(i int) bool { return less(b[mb], a[i]) })
+	dst[ma+mb] = b[mb]
+	f.fork(func() {
+		parallelMerge(f, a[:ma], b[:mb], dst[:ma+mb], less)
+	}, func() {
+		parallelMerge(f, a[ma:], b[mb+1:], dst[ma+mb+1:], less)
+	})
+}
+
+// mergeInto merges the sorted a and b into dst, a goes first on ties
+func mergeInto[T any](a, b, dst []T, less func(a, b T) bool) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if less(b[j], a[i]) {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// searchFirst returns the first index in [0, n) for which f
+// is true, or n, f must be false and then true over [0, n).
+func searchFirst(n int, f func(int) bool) int {
+	lo, hi := 0, n
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if f(mid) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
+// ParallelQuickSort is a O(nlog(n)) unstable sorting algorithm, it partitions
+// data like PdqSort and sorts both sides on goroutines down to parts of
+// opts.Grain, which are sorted with PdqSort. Less and Swap are called
+// concurrently on distinct parts of data and must be safe for it.
+func ParallelQuickSort(data Sortable, opts ParallelOptions) {
+	n := data.Len()
+	parallelQuickSort(newForker(opts), data, 0, n, bits.Len(uint(n)), true)
+}
+
+// ParallelQuickSortSlice sorts s in the order of compare with ParallelQuickSort
+func ParallelQuickSortSlice[T any](s []T, compare func(a, b T) int, opts ParallelOptions) {
+	ParallelQuickSort(&funcSlice[T]{s: s, compare: compare}, opts)
+}
+
+// ParallelQuickSortOrdered sorts s in increasing order with ParallelQuickSort
+func ParallelQuickSortOrdered[T cmp.Ordered](s []T, opts ParallelOptions) {
+	ParallelQuickSort(orderedSlice[T](s), opts)
+}
+
+// parallelQuickSort sorts data[a:b], limit is the number of unbalanced
+// partitions allowed before heapsort as in pdqsort.
+func parallelQuickSort(f *forker, data Sortable, a, b, limit int, wasBalanced bool) {
+	length := b - a
+	if length <= f.grain {
+		pdqsort(data, a, b, limit)
+		return
+	}
+	if limit == 0 {
+		heapSort(data, a, b)
+		return
+	}
+	if !wasBalanced {
+		breakPatterns(data, a, b)
+		limit--
+	}
+	pivot, _ := choosePivot(data, a, b)
+	// data[a-1] is a pivot of an enclosing partition, see pdqsort
+	if a > 0 && !data.Less(a-1, pivot) {
+		parallelQuickSort(f, data, partitionEqual(data, a, b, pivot), b, limit, true)
+		return
+	}
+	mid, _ := partition(data, a, b, pivot)
+	balanced := min(mid-a, b-mid) >= length/8
+	f.fork(func() {
+		parallelQuickSort(f, data, a, mid, limit, balanced)
+	}, func() {
+		parallelQuickSort(f, data, mid+1, b, limit, balanced)
+	})
+}
@@ -0,0 +1,102 @@
+package sort_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+)
+
+var parallelOptions = []isort.ParallelOptions{
+	{},
+	{Workers: 1},
+	{Workers: 3, Grain: 2},
+	{Workers: 8, Grain: 64},
+}
+
+func TestParallelMergeSort(t *testing.T) {
+	for _, opts := range parallelOptions {
+		for _, n := range []int{0, 1, 2, 100, 10000, 100000} {
+			s := make([]pair, n)
+			for i := range s {
+				s[i] = pair{key: rand.Intn(n/10 + 1), pos: i}
+			}
+			want := slices.Clone(s)
+			slices.SortStableFunc(want, comparePairs)
+
+			isort.ParallelMergeSortSlice(s, comparePairs, opts)
+			if !slices.Equal(s, want) {
+				t.Fatalf("%+v of %d: not stable", opts, n)
+			}
+
+			a := isort.RandomArray(n, n+1)
+			isort.ParallelMergeSort(a, opts)
+			if !slices.IsSorted(a) {
+				t.Fatalf("%+v of %d ints: not sorted", opts, n)
+			}
+		}
+	}
+}
+
+func TestParallelQuickSort(t *testing.T) {
+	for _, opts := range parallelOptions {
+		for _, n := range []int{0, 1, 2, 100, 10000, 100000} {
+			a := []int(isort.RandomArray(n, n+1))
+			isort.ParallelQuickSortOrdered(a, opts)
+			if !slices.IsSorted(a) {
+				t.Fatalf("%+v of %d ints: not sorted", opts, n)
+			}
+		}
+		for name, gen := range killers {
+			n := 10000
+			a := isort.IntSlice(gen(n))
+			isort.ParallelQuickSort(a, opts)
+			if !slices.IsSorted(a) {
+				t.Errorf("%+v %s of %d: not sorted", opts, name, n)
+			}
+		}
+	}
+}
+
+const benchSize = 1 << 20
+
+func benchmarkInts(b *testing.B, sort func(s []int)) {
+	src := []int(isort.RandomArray(benchSize, benchSize))
+	s := make([]int, len(src))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(s, src)
+		b.StartTimer()
+		sort(s)
+	}
+}
+
+func BenchmarkMergeSort(b *testing.B) {
+	benchmarkInts(b, isort.MergeSortOrdered[int])
+}
+
+func BenchmarkTimSort(b *testing.B) {
+	benchmarkInts(b, isort.TimSortOrdered[int])
+}
+
+func BenchmarkParallelMergeSort(b *testing.B) {
+	benchmarkInts(b, func(s []int) { isort.ParallelMergeSortOrdered(s, isort.ParallelOptions{}) })
+}
+
+func BenchmarkParallelMergeSortOneWorker(b *testing.B) {
+	benchmarkInts(b, func(s []int) { isort.ParallelMergeSortOrdered(s, isort.ParallelOptions{Workers: 1}) })
+}
+
+func BenchmarkPdqSort(b *testing.B) {
+	benchmarkInts(b, isort.PdqSortOrdered[int])
+}
+
+func BenchmarkParallelQuickSort(b *testing.B) {
+	benchmarkInts(b, func(s []int) { isort.ParallelQuickSortOrdered(s, isort.ParallelOptions{}) })
+}
+
+func BenchmarkParallelQuickSortOneWorker(b *testing.B) {
+	benchmarkInts(b, func(s []int) { isort.ParallelQuickSortOrdered(s, isort.ParallelOptions{Workers: 1}) })
+}
@@ -0,0 +1,288 @@
+package sort
+
+import (
+	"cmp"
+	"math/bits"
+)
+
+const (
+	// pdqInsertionMax is the longest slice PdqSort sorts by insertion
+	pdqInsertionMax = 12
+	// pdqNintherMin is the shortest slice whose pivot is a ninther
+	pdqNintherMin = 50
+	// pdqPartialSteps bounds the elements partialInsertionSort moves
+	// and pdqPartialMin is the shortest slice it may move them in.
+	pdqPartialSteps = 5
+	pdqPartialMin   = 50
+)
+
+// sortedHint is what choosePivot learned about the order of a slice
+type sortedHint int
+
+const (
+	unknownHint sortedHint = iota
+	increasingHint
+	decreasingHint
+)
+
+// PdqSort is a O(nlog(n)) unstable sorting algorithm, a quicksort which
+// switches to heapsort after too many unbalanced partitions, so that
+// no input takes O(n^2), and which sorts presorted inputs in O(n).
+func PdqSort(data Sortable) {
+	n := data.Len()
+	pdqsort(data, 0, n, bits.Len(uint(n)))
+}
+
+// pdqsort sorts data[a:b], limit is the number of
+// unbalanced partitions allowed before heapsort.
+func pdqsort(data Sortable, a, b, limit int) {
+	wasBalanced, wasPartitioned := true, true
+	for {
+		length := b - a
+		if length <= pdqInsertionMax {
+			insertionSort(data, a, b)
+			return
+		}
+		if limit == 0 {
+			heapSort(data, a, b)
+			return
+		}
+		// shuffle some elements after an unbalanced partition
+		// to break the pattern which made the pivot bad
+		if !wasBalanced {
+			breakPatterns(data, a, b)
+			limit--
+		}
+
+		pivot, hint := choosePivot(data, a, b)
+		if hint == decreasingHint {
+			reverseRange(data, a, b)
+			pivot = (b - 1) - (pivot - a)
+			hint = increasingHint
+		}
+		// the slice looks sorted, finish it if few elements are out of place
+		if wasBalanced && wasPartitioned && hint == increasingHint {
+			if partialInsertionSort(data, a, b) {
+				return
+			}
+		}
+		// data[a-1] is the pivot of the parent partition, which no element of
+		// the slice is less than. If it equals the pivot, so many elements are
+		// equal that they are put aside at once.
+		if a > 0 && !data.Less(a-1, pivot) {
+			a = partitionEqual(data, a, b, pivot)
+			continue
+		}
+
+		mid, alreadyPartitioned := partition(data, a, b, pivot)
+		wasPartitioned = alreadyPartitioned
+		left, right := mid-a, b-mid
+		if left < right {
+			wasBalanced = left >= length/8
+			pdqsort(data, a, mid, limit)
+			a = mid + 1
+		} else {
+			wasBalanced = right >= length/8
+			pdqsort(data, mid+1, b, limit)
+			b = mid
+		}
+	}
+}
+
+// partition moves the elements of data[a:b] less than the pivot before it and
+// the others after it, it returns the index of the pivot and whether no element
+// had to move.
+func partition(data Sortable, a, b, pivot int) (int, bool) {
+	data.Swap(a, pivot)
+	i, j := a+1, b-1
+	for i <= j && data.Less(i, a) {
+		i++
+	}
+	for i <= j && !data.Less(j, a) {
+		j--
+	}
+	if i > j {
+		data.Swap(j, a)
+		return j, true
+	}
+	data.Swap(i, j)
+	i++
+	j--
+	for {
+		for i <= j && data.Less(i, a) {
+			i++
+		}
+		for i <= j && !data.Less(j, a) {
+			j--
+		}
+		if i > j {
+			break
+		}
+		data.Swap(i, j)
+		i++
+		j--
+	}
+	data.Swap(j, a)
+	return j, false
+}
+
+// partitionEqual moves the elements of data[a:b] equal to the pivot, which
+// no element is less than, to the front and returns the index after them.
+func partitionEqual(data Sortable, a, b, pivot int) int {
+	data.Swap(a, pivot)
+	i, j := a+1, b-1
+	for {
+		for i <= j && !data.Less(a, i) {
+			i++
+		}
+		for i <= j && data.Less(a, j) {
+			j--
+		}
+		if i > j {
+			break
+		}
+		data.Swap(i, j)
+		i++
+		j--
+	}
+	return i
+}
+
+// partialInsertionSort sorts data[a:b] if a few elements out of place
+// are enough to do it, it reports whether data[a:b] is sorted.
+func partialInsertionSort(data Sortable, a, b int) bool {
+	i := a + 1
+	for step := 0; step < pdqPartialSteps; step++ {
+		for i < b && !data.Less(i, i-1) {
+			i++
+		}
+		if i == b {
+			return true
+		}
+		if b-a < pdqPartialMin {
+			return false
+		}
+		data.Swap(i, i-1)
+		// shift the smaller one to the left
+		for j := i - 1; j > a && data.Less(j, j-1); j-- {
+			data.Swap(j, j-1)
+		}
+		// shift the greater one to the right
+		for j := i + 1; j < b && data.Less(j, j-1); j++ {
+			data.Swap(j, j-1)
+		}
+	}
+	return false
+}
+
+// breakPatterns swaps three elements around the middle
+// of data[a:b] with pseudo random ones.
+func breakPatterns(data Sortable, a, b int) {
+	length := b - a
+	if length < 8 {
+		return
+	}
+	random := xorshift(length)
+	mask := uint64(1)<<bits.Len(uint(length)) - 1
+	idx := a + length/4*2 - 1
+	for i := 0; i < 3; i++ {
+		other := int(random.next() & mask)
+		if other >= length {
+			other -= length
+		}
+		data.Swap(idx-1+i, a+other)
+	}
+}
+
+// xorshift is a pseudo random generator, it is seeded
+// with the length of the slice so that sorts repeat.
+type xorshift uint64
+
+func (r *xorshift) next() uint64 {
+	*r ^= *r << 13
+	*r ^= *r >> 7
+	*r ^= *r << 17
+	return uint64(*r)
+}
+
+// choosePivot returns the median of three elements of data[a:b], or the
+// median of three medians of adjacent elements for long slices. The hint
+// is increasing if no element was out of order and decreasing if all were.
+func choosePivot(data Sortable, a, b int) (int, sortedHint) {
+	const maxSwaps = 4 * 3
+	length := b - a
+	swaps := 0
+	i, j, k := a+length/4, a+length/4*2, a+length/4*3
+	if length >= 8 {
+		if length >= pdqNintherMin {
+			i = medianAdjacent(data, i, &swaps)
+			j = medianAdjacent(data, j, &swaps)
+			k = medianAdjacent(data, k, &swaps)
+		}
+		j = median(data, i, j, k, &swaps)
+	}
+	switch swaps {
+	case 0:
+		return j, increasingHint
+	case maxSwaps:
+		return j, decreasingHint
+	}
+	return j, unknownHint
+}
+
+// order2 returns a and b in the order of their elements
+func order2(data Sortable, a, b int, swaps *int) (int, int) {
+	if data.Less(b, a) {
+		*swaps++
+		return b, a
+	}
+	return a, b
+}
+
+// median returns the index of the median element of a, b and c
+func median(data Sortable, a, b, c int, swaps *int) int {
+	a, b = order2(data, a, b, swaps)
+	b, c = order2(data, b, c, swaps)
+	_, b = order2(data, a, b, swaps)
+	return b
+}
+
+// medianAdjacent returns the index of the median of a and its neighbours
+func medianAdjacent(data Sortable, a int, swaps *int) int {
+	return median(data, a-1, a, a+1, swaps)
+}
+
+// reverseRange reverses data[a:b]
+func reverseRange(data Sortable, a, b int) {
+	for i, j := a, b-1; i < j; i, j = i+1, j-1 {
+		data.Swap(i, j)
+	}
+}
+
+// PdqSortSlice sorts s in the order of compare with PdqSort
+func PdqSortSlice[T any](s []T, compare func(a, b T) int) {
+	PdqSort(&funcSlice[T]{s: s, compare: compare})
+}
+
+// PdqSortOrdered sorts s in increasing order with PdqSort
+func PdqSortOrdered[T cmp.Ordered](s []T) {
+	PdqSort(orderedSlice[T](s))
+}
+
+/*
+Complexity of pattern-defeating quicksort：
+	* Best: 	O(n)
+	* Average: 	O(nlog(n))
+	* Worst: 	O(nlog(n))
+	* Memory: 	O(log(n))
+	* Stable: 	No
+	* Paper: 	https://arxiv.org/abs/2106.05123
+Shortcome from the paper:
+	Pattern-defeating quicksort (pdqsort) is a sorting algorithm
+	that combines the fast average case of randomized quicksort
+	with the fast worst case of heapsort, while achieving linear
+	time on inputs with certain patterns. It is an extension of
+	introsort: a bad pivot choice is detected and answered by
+	shuffling elements to break the pattern, and once too many
+	bad choices are made the slice is sorted with heapsort.
+*/
@@ -0,0 +1,158 @@
+package sort_test
+
+import (
+	"math/bits"
+	"sort"
+	"testing"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+)
+
+// countingInts counts the comparisons of a sort and fails
+// the test once they pass max.
+type countingInts struct {
+	t    *testing.T
+	data isort.IntSlice
+	ncmp int
+	max  int
+}
+
+func (d *countingInts) Len() int { return len(d.data) }
+func (d *countingInts) Less(i, j int) bool {
+	if d.ncmp++; d.ncmp > d.max {
+		d.t.Fatalf("used more than %d comparisons", d.max)
+	}
+	return d.data[i] < d.data[j]
+}
+func (d *countingInts) Equal(i, j int) bool { return d.data[i] == d.data[j] }
+func (d *countingInts) Swap(i, j int)       { d.data[i], d.data[j] = d.data[j], d.data[i] }
+
+// maxComparisons is a O(nlog(n)) bound for sorts of n elements
+func maxComparisons(n int) int {
+	return 4*n*bits.Len(uint(n)) + 100
+}
+
+// killers are inputs which push naive quicksorts to O(n^2)
+var killers = map[string]func(n int) []int{
+	"sorted": func(n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i
+		}
+		return s
+	},
+	"reversed": func(n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = n - i
+		}
+		return s
+	},
+	"equal": func(n int) []int {
+		return make([]int, n)
+	},
+	"organ pipe": func(n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = min(i, n-i)
+		}
+		return s
+	},
+	"sawtooth": func(n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i % 64
+		}
+		return s
+	},
+	"few keys": func(n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i * 7919 % 3
+		}
+		return s
+	},
+	// Musser's median of three killer for an even n
+	"median of three": func(n int) []int {
+		s := make([]int, n)
+		k := n / 2
+		for i := 1; i <= k; i++ {
+			if i%2 == 1 {
+				s[i-1] = i
+			} else {
+				s[i-1] = k + i - 1
+			}
+			s[k+i-1] = 2 * i
+		}
+		return s
+	},
+}
+
+func TestPdqSortKillers(t *testing.T) {
+	for name, gen := range killers {
+		for _, n := range []int{100, 1001, 10000} {
+			d := &countingInts{t: t, data: gen(n), max: maxComparisons(n)}
+			isort.PdqSort(d)
+			if !sort.IsSorted(d.data) {
+				t.Errorf("%s of %d: not sorted", name, n)
+			}
+		}
+	}
+}
+
+func TestPdqSortPresorted(t *testing.T) {
+	for _, name := range []string{"sorted", "reversed", "equal"} {
+		n := 10000
+		d := &countingInts{t: t, data: killers[name](n), max: 2 * n}
+		isort.PdqSort(d)
+		if !sort.IsSorted(d.data) {
+			t.Errorf("%s of %d: not sorted", name, n)
+		}
+	}
+}
+
+// adversary is McIlroy's "killer adversary for quicksort", it decides the
+// order of the elements as the sort compares them so that the pivot of
+// each partition is as bad as possible. Elements are gas until they are
+// compared with another gas element, then they become solid values.
+type adversary struct {
+	countingInts
+	nsolid    int
+	candidate int
+	gas       int
+}
+
+func newAdversary(t *testing.T, n int) *adversary {
+	d := &adversary{countingInts: countingInts{t: t, data: make([]int, n), max: maxComparisons(n)}, gas: n - 1}
+	for i := range d.data {
+		d.data[i] = d.gas
+	}
+	return d
+}
+
+func (d *adversary) Less(i, j int) bool {
+	if d.data[i] == d.gas && d.data[j] == d.gas {
+		if i == d.candidate {
+			d.data[i] = d.nsolid
+		} else {
+			d.data[j] = d.nsolid
+		}
+		d.nsolid++
+	}
+	if d.data[i] == d.gas {
+		d.candidate = i
+	} else if d.data[j] == d.gas {
+		d.candidate = j
+	}
+	return d.countingInts.Less(i, j)
+}
+
+func TestPdqSortAdversary(t *testing.T) {
+	for _, n := range []int{100, 1000, 10000} {
+		d := newAdversary(t, n)
+		isort.PdqSort(d)
+		if !sort.IsSorted(d.data) {
+			t.Errorf("adversary of %d: not sorted", n)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package sort
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrRange is returned by PigeonholeSort when the range of its input needs too many holes
+var ErrRange = errors.New("sort: range of keys too wide")
+
+// PigeonholeSort is a O(n+r) sorting algorithm for ints, r being the range of s. It
+// counts the elements of each value in a hole and writes them back in order. If more
+// than maxHoles holes would be needed it returns ErrRange and leaves s unchanged,
+// MaxBuckets is the limit if maxHoles is 0.
+func PigeonholeSort(s []int, maxHoles int) error {
+	if len(s) < 2 {
+		return nil
+	}
+	if maxHoles <= 0 {
+		maxHoles = MaxBuckets
+	}
+	lo, hi := s[0], s[0]
+	for _, v := range s {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	// the difference of the extreme ints overflows an int but not a uint
+	if r := uint(hi) - uint(lo); r >= uint(maxHoles) {
+		return fmt.Errorf("%w: %d holes for [%d, %d] over the limit %d", ErrRange, r+1, lo, hi, maxHoles)
+	}
+	holes := make([]int, hi-lo+1)
+	for _, v := range s {
+		holes[v-lo]++
+	}
+	i := 0
+	for h, n := range holes {
+		for ; n > 0; n-- {
+			s[i] = lo + h
+			i++
+		}
+	}
+	return nil
+}
+
+/*
+Complexity of pigeonhole sort：
+	* Best: 	O(n + r)
+	* Average: 	O(n + r)
+	* Worst: 	O(n + r)
+	* Memory: 	O(r)
+	* Stable: 	Yes
+	* Wiki: 	https://en.wikipedia.org/wiki/Pigeonhole_sort
+Shortcome from wiki:
+	Pigeonhole sorting is a sorting algorithm that is suitable for sorting
+	lists of elements where the number n of elements and the length N of
+	the range of possible key values are approximately the same. It
+	requires O(n + N) time. It is similar to counting sort, but differs
+	in that it "moves items twice: once to the bucket array and again to
+	the final destination", whereas counting sort builds an auxiliary
+	array then uses the array to compute each item's final destination.
+*/
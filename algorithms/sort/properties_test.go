@@ -0,0 +1,267 @@
+package sort_test
+
+import (
+	"math/bits"
+	"slices"
+	"strings"
+	"testing"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+	"github.com/man-fish/goalgorithms/algorithms/sort/sorttest"
+)
+
+type item = sorttest.Item
+
+// forms adapts a sort in its three forms, quadratic sorts set maxLen
+func forms(name string, stable bool, maxLen int, data func(isort.Sortable), slice func([]item, func(a, b item) int), ordered func([]int)) []sorttest.Sorter {
+	s := []sorttest.Sorter{
+		sorttest.FromSortable(name, stable, data),
+		sorttest.FromSlice(name+"Slice", stable, slice),
+		sorttest.FromInts(name+"Ordered", true, func(keys []int) []int {
+			ordered(keys)
+			return keys
+		}),
+	}
+	for i := range s {
+		s[i].MaxLen = maxLen
+	}
+	return s
+}
+
+// parallel runs the parallel sorts with a small grain so that small inputs fork
+var parallel = isort.ParallelOptions{Grain: 16, Workers: 4}
+
+func powerOfTwo(n int) bool { return n&(n-1) == 0 }
+
+// sorters holds the adapted forms of every sort by algorithm
+var sorters = map[string][]sorttest.Sorter{
+	"BubbleSort":       forms("BubbleSort", true, 1000, isort.BubbleSort, isort.BubbleSortSlice[item], isort.BubbleSortOrdered[int]),
+	"HeapSort":         forms("HeapSort", false, 0, isort.HeapSort, isort.HeapSortSlice[item], isort.HeapSortOrdered[int]),
+	"InPlaceMergeSort": forms("InPlaceMergeSort", true, 0, isort.InPlaceMergeSort, isort.InPlaceMergeSortSlice[item], isort.InPlaceMergeSortOrdered[int]),
+	"InsertionSort":    forms("InsertionSort", true, 1000, isort.InsertionSort, isort.InsertionSortSlice[item], isort.InsertionSortOrdered[int]),
+	"MergeSort":        forms("MergeSort", true, 0, isort.MergeSort, isort.MergeSortSlice[item], isort.MergeSortOrdered[int]),
+	"PdqSort":          forms("PdqSort", false, 0, isort.PdqSort, isort.PdqSortSlice[item], isort.PdqSortOrdered[int]),
+	"QuickSort":        forms("QuickSort", false, 0, isort.QuickSort, isort.QuickSortSlice[item], isort.QuickSortOrdered[int]),
+	"SelectSort":       forms("SelectSort", false, 1000, isort.SelectSort, isort.SelectSortSlice[item], isort.SelectSortOrdered[int]),
+	"ShellSort":        forms("ShellSort", false, 0, isort.ShellSort, isort.ShellSortSlice[item], isort.ShellSortOrdered[int]),
+	"SortSmall":        forms("SortSmall", false, 0, isort.SortSmall, isort.SortSmallSlice[item], isort.SortSmallOrdered[int]),
+	"TimSort":          forms("TimSort", true, 0, isort.TimSort, isort.TimSortSlice[item], isort.TimSortOrdered[int]),
+	"ParallelMergeSort": forms("ParallelMergeSort", true, 0,
+		func(data isort.Sortable) { isort.ParallelMergeSort(data, parallel) },
+		func(s []item, compare func(a, b item) int) { isort.ParallelMergeSortSlice(s, compare, parallel) },
+		func(s []int) { isort.ParallelMergeSortOrdered(s, parallel) }),
+	"ParallelQuickSort": forms("ParallelQuickSort", false, 0,
+		func(data isort.Sortable) { isort.ParallelQuickSort(data, parallel) },
+		func(s []item, compare func(a, b item) int) { isort.ParallelQuickSortSlice(s, compare, parallel) },
+		func(s []int) { isort.ParallelQuickSortOrdered(s, parallel) }),
+	"BitonicSort": withLen(powerOfTwo, forms("BitonicSort", false, 0,
+		func(data isort.Sortable) { isort.BitonicSort(data, parallel) },
+		func(s []item, compare func(a, b item) int) { isort.BitonicSortSlice(s, compare, parallel) },
+		func(s []int) { isort.BitonicSortOrdered(s, parallel) })),
+	"SortByKey": {
+		sorttest.FromSlice("SortByKey", false, func(s []item, _ func(a, b item) int) {
+			isort.SortByKey(s, func(it item) int { return it.Key })
+		}),
+		sorttest.FromSlice("SortByKeyString", false, func(s []item, _ func(a, b item) int) {
+			isort.SortByKey(s, func(it item) string { return orderedString(it.Key) })
+		}),
+	},
+	"StableSortByKey": {
+		sorttest.FromSlice("StableSortByKey", true, func(s []item, _ func(a, b item) int) {
+			isort.StableSortByKey(s, func(it item) int { return it.Key })
+		}),
+		sorttest.FromSlice("StableSortByKeyString", true, func(s []item, _ func(a, b item) int) {
+			isort.StableSortByKey(s, func(it item) string { return orderedString(it.Key) })
+		}),
+	},
+	"CountingSort": {
+		sorttest.FromInts("CountingSort", false, isort.CountingSort),
+		sorttest.FromSlice("CountingSortSlice", true, func(s []item, _ func(a, b item) int) {
+			isort.CountingSortSlice(s, func(it item) int { return it.Key })
+		}),
+		sorttest.FromInts("CountingSortOrdered", true, func(keys []int) []int {
+			isort.CountingSortOrdered(keys)
+			return keys
+		}),
+	},
+	"RadixSort": {
+		nonNegative(sorttest.FromInts("RadixSort", false, isort.RadixSort)),
+		sorttest.FromSlice("RadixSortSlice", true, func(s []item, _ func(a, b item) int) {
+			isort.RadixSortSlice(s, func(it item) int { return it.Key })
+		}),
+		sorttest.FromInts("RadixSortOrdered", true, func(keys []int) []int {
+			isort.RadixSortOrdered(keys)
+			return keys
+		}),
+	},
+	"RadixSortInt64": {sorttest.FromInts("RadixSortInt64", true, func(keys []int) []int {
+		s := make([]int64, len(keys))
+		for i, k := range keys {
+			s[i] = int64(k)
+		}
+		isort.RadixSortInt64(s)
+		for i, v := range s {
+			keys[i] = int(v)
+		}
+		return keys
+	})},
+	"RadixSortUint64": {nonNegative(sorttest.FromInts("RadixSortUint64", true, func(keys []int) []int {
+		s := make([]uint64, len(keys))
+		for i, k := range keys {
+			s[i] = uint64(k)
+		}
+		isort.RadixSortUint64(s)
+		for i, v := range s {
+			keys[i] = int(v)
+		}
+		return keys
+	}))},
+	"RadixSortFloat64": {sorttest.FromFloats("RadixSortFloat64", isort.RadixSortFloat64)},
+	"RadixSortBy": {sorttest.FromSlice("RadixSortBy", true, func(s []item, _ func(a, b item) int) {
+		isort.RadixSortBy(s, func(it item) uint64 { return uint64(it.Key) ^ 1<<63 })
+	})},
+	"RadixSortStrings": {sorttest.FromInts("RadixSortStrings", true, func(keys []int) []int {
+		s := make([]string, len(keys))
+		for i, k := range keys {
+			s[i] = orderedString(k)
+		}
+		isort.RadixSortStrings(s)
+		for i, v := range s {
+			keys[i] = orderedKey(v)
+		}
+		return keys
+	})},
+	"RadixSortBytes": {sorttest.FromInts("RadixSortBytes", true, func(keys []int) []int {
+		s := make([][]byte, len(keys))
+		for i, k := range keys {
+			s[i] = []byte(orderedString(k))
+		}
+		isort.RadixSortBytes(s)
+		for i, v := range s {
+			keys[i] = orderedKey(string(v))
+		}
+		return keys
+	})},
+	"PigeonholeSort": {withKeys(-1<<16, 1<<16, sorttest.FromInts("PigeonholeSort", true, func(keys []int) []int {
+		if err := isort.PigeonholeSort(keys, 0); err != nil {
+			panic(err)
+		}
+		return keys
+	}))},
+	"BucketSort": {
+		sorttest.FromFloats("BucketSort", func(s []float64) { isort.BucketSort(s, isort.BucketOptions{}) }),
+		sorttest.FromFloats("BucketSortPdq", func(s []float64) {
+			isort.BucketSort(s, isort.BucketOptions{Buckets: 7, Sort: isort.PdqSortOrdered[float64]})
+		}),
+	},
+	"FlashSort": {sorttest.FromFloats("FlashSort", isort.FlashSort)},
+}
+
+// orderedString encodes k so that the strings are in the order of the keys
+func orderedString(k int) string {
+	var b strings.Builder
+	u := uint64(k) ^ 1<<63
+	// variable lengths and common prefixes
+	for i := bits.Len64(u)/8 + 1; i > 0; i-- {
+		b.WriteByte(byte(u >> (8 * (i - 1))))
+	}
+	return string(rune('0'+bits.Len64(u)/8)) + b.String()
+}
+
+// orderedKey decodes the key of orderedString
+func orderedKey(s string) int {
+	var u uint64
+	for i := 1; i < len(s); i++ {
+		u = u<<8 | uint64(s[i])
+	}
+	return int(u ^ 1<<63)
+}
+
+func withLen(valid func(n int) bool, s []sorttest.Sorter) []sorttest.Sorter {
+	for i := range s {
+		s[i].ValidLen = valid
+	}
+	return s
+}
+
+func withKeys(lo, hi int, s sorttest.Sorter) sorttest.Sorter {
+	s.MinKey, s.MaxKey = lo, hi
+	return s
+}
+
+func nonNegative(s sorttest.Sorter) sorttest.Sorter {
+	return withKeys(0, 1<<63-1, s)
+}
+
+func TestProperties(t *testing.T) {
+	names := slices.Sorted(func(yield func(string) bool) {
+		for name := range sorters {
+			if !yield(name) {
+				return
+			}
+		}
+	})
+	for _, name := range names {
+		for _, s := range sorters[name] {
+			sorttest.Run(t, s)
+		}
+	}
+}
+
+// fuzz registers the fuzz target of every form of the sort name
+func fuzz(f *testing.F, name string) {
+	sorttest.Fuzz(f, sorters[name]...)
+}
+
+func FuzzBitonicSort(f *testing.F)       { fuzz(f, "BitonicSort") }
+func FuzzBubbleSort(f *testing.F)        { fuzz(f, "BubbleSort") }
+func FuzzBucketSort(f *testing.F)        { fuzz(f, "BucketSort") }
+func FuzzCountingSort(f *testing.F)      { fuzz(f, "CountingSort") }
+func FuzzFlashSort(f *testing.F)         { fuzz(f, "FlashSort") }
+func FuzzHeapSort(f *testing.F)          { fuzz(f, "HeapSort") }
+func FuzzInPlaceMergeSort(f *testing.F)  { fuzz(f, "InPlaceMergeSort") }
+func FuzzInsertionSort(f *testing.F)     { fuzz(f, "InsertionSort") }
+func FuzzMergeSort(f *testing.F)         { fuzz(f, "MergeSort") }
+func FuzzParallelMergeSort(f *testing.F) { fuzz(f, "ParallelMergeSort") }
+func FuzzParallelQuickSort(f *testing.F) { fuzz(f, "ParallelQuickSort") }
+func FuzzPdqSort(f *testing.F)           { fuzz(f, "PdqSort") }
+func FuzzPigeonholeSort(f *testing.F)    { fuzz(f, "PigeonholeSort") }
+func FuzzQuickSort(f *testing.F)         { fuzz(f, "QuickSort") }
+func FuzzRadixSort(f *testing.F)         { fuzz(f, "RadixSort") }
+func FuzzRadixSortBy(f *testing.F)       { fuzz(f, "RadixSortBy") }
+func FuzzRadixSortBytes(f *testing.F)    { fuzz(f, "RadixSortBytes") }
+func FuzzRadixSortFloat64(f *testing.F)  { fuzz(f, "RadixSortFloat64") }
+func FuzzRadixSortInt64(f *testing.F)    { fuzz(f, "RadixSortInt64") }
+func FuzzRadixSortStrings(f *testing.F)  { fuzz(f, "RadixSortStrings") }
+func FuzzRadixSortUint64(f *testing.F)   { fuzz(f, "RadixSortUint64") }
+func FuzzSelectSort(f *testing.F)        { fuzz(f, "SelectSort") }
+func FuzzShellSort(f *testing.F)         { fuzz(f, "ShellSort") }
+func FuzzSortByKey(f *testing.F)         { fuzz(f, "SortByKey") }
+func FuzzSortSmall(f *testing.F)         { fuzz(f, "SortSmall") }
+func FuzzStableSortByKey(f *testing.F)   { fuzz(f, "StableSortByKey") }
+func FuzzTimSort(f *testing.F)           { fuzz(f, "TimSort") }
+
+// FuzzNthElement checks the selection sorts, which sort only a part of their input
+func FuzzNthElement(f *testing.F) {
+	f.Add([]byte{3, 1, 2}, uint8(1))
+	f.Add([]byte("the quick brown fox jumps over the lazy dog"), uint8(20))
+	f.Fuzz(func(t *testing.T, data []byte, k uint8) {
+		if len(data) == 0 {
+			return
+		}
+		src := make([]int, len(data))
+		for i, b := range data {
+			src[i] = int(int8(b))
+		}
+		want := slices.Sorted(slices.Values(src))
+		i := int(k) % len(src)
+		s := slices.Clone(src)
+		isort.NthElementOrdered(s, i)
+		checkNth(t, "NthElement", s, want, i)
+		s = slices.Clone(src)
+		isort.PartialSortOrdered(s, i)
+		if !slices.Equal(s[:i], want[:i]) || !slices.Equal(slices.Sorted(slices.Values(s)), want) {
+			t.Fatalf("PartialSort of %d at %d: wanted %v but get %v", len(s), i, want[:i], s)
+		}
+	})
+}
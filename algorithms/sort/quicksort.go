@@ -1,55 +1,63 @@
 package sort
 
-// QuickSort is a O(nlog(n)) stable sorting algorithm
-func QuickSort(data []int) {
-	quickSort(data, 0, len(data)-1)
-}
+import "cmp"
 
-func quickSort(data []int, lo, hi int) {
-	if len(data) == 0 {
-		return
-	}
-	mlo, mhi := lo, hi
-	pivot := data[int(uint(mlo+mhi)>>1)]
+// QuickSort is a O(nlog(n)) unstable sorting algorithm, it takes O(n^2)
+// on inputs which defeat its middle pivot, PdqSort does not.
+func QuickSort(data Sortable) {
+	quickSort(data, 0, data.Len()-1)
+}
 
-	for mlo < mhi {
-		for data[mlo] < pivot {
+// quickSort sorts data[lo..hi] around the middle element, it recurses
+// into the smaller part and loops on the larger one.
+func quickSort(data Sortable, lo, hi int) {
+	for lo < hi {
+		// the pivot waits at lo while the rest is partitioned
+		data.Swap(lo, int(uint(lo+hi)>>1))
+		mlo, mhi := lo+1, hi
+		for {
+			for mlo <= mhi && data.Less(mlo, lo) {
+				mlo++
+			}
+			for mlo <= mhi && data.Less(lo, mhi) {
+				mhi--
+			}
+			if mlo >= mhi {
+				break
+			}
+			data.Swap(mlo, mhi)
 			mlo++
-		}
-		for pivot < data[mhi] {
 			mhi--
 		}
-		if mhi <= mlo {
-			break
-		}
-		data[mlo], data[mhi] = data[mhi], data[mlo]
-		if data[mlo] == pivot {
-			mhi--
-		}
-		if data[mhi] == pivot {
-			mlo++
+		data.Swap(lo, mhi)
+
+		if mhi-lo < hi-mhi {
+			quickSort(data, lo, mhi-1)
+			lo = mhi + 1
+		} else {
+			quickSort(data, mhi+1, hi)
+			hi = mhi - 1
 		}
 	}
+}
 
-	if mlo == mhi {
-		mlo++
-		mhi--
-	}
-	if mlo < hi {
-		quickSort(data, mlo, hi)
-	}
-	if mhi > lo {
-		quickSort(data, lo, mhi)
-	}
+// QuickSortSlice sorts s in the order of compare with QuickSort
+func QuickSortSlice[T any](s []T, compare func(a, b T) int) {
+	QuickSort(&funcSlice[T]{s: s, compare: compare})
+}
+
+// QuickSortOrdered sorts s in increasing order with QuickSort
+func QuickSortOrdered[T cmp.Ordered](s []T) {
+	QuickSort(orderedSlice[T](s))
 }
 
 /*
 Complexity of quick sort：
 	* Best: 	O(nlog(n))
 	* Average: 	O(nlog(n))
-	* Worst: 	O(nlog(n))
-	* Memory: 	O(n^2)
-	* Stable: 	Yes
+	* Worst: 	O(n^2)
+	* Memory: 	O(log(n))
+	* Stable: 	No
 	* Wiki: 	https://en.wikipedia.org/wiki/Quicksort
 Shortcome from wiki:
 	Quicksort is a divide and conquer algorithm.
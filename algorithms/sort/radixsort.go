@@ -5,11 +5,10 @@ import (
 	"strconv"
 )
 
-// RadixSort is a O(n*k) stable sorting algorithm
+// RadixSort is a O(n*k) stable sorting algorithm for non-negative ints,
+// it sorts by decimal digits, RadixSortInt64 sorts any int64 by bytes.
 func RadixSort(intArr []int) []int {
-	tmp := make([]int, len(intArr))
-	copy(tmp, intArr)
-	digits := MaxDigits(tmp)
+	digits := MaxDigits(intArr)
 	bucket := make([]int, digits)
 
 	for index := range bucket {
@@ -20,14 +19,29 @@ func RadixSort(intArr []int) []int {
 	return intArr
 }
 
-// MaxDigits returns the biggest digit among nums
+// RadixSortSlice sorts s stably in the increasing order of key with a radix sort,
+// key is called once for each element. Radix sorts need keys instead of comparisons,
+// so there is no form for a Sortable: RadixSort takes []int. The keys are sorted by
+// bytes with RadixSortBy, the decimal digits of RadixSort suit only non-negative keys.
+func RadixSortSlice[T any, K Integer](s []T, key func(T) K) {
+	RadixSortBy(s, func(v T) uint64 { return integerBits(key(v)) })
+}
+
+// RadixSortOrdered sorts s in increasing order with RadixSortSlice
+func RadixSortOrdered[T Integer](s []T) {
+	RadixSortSlice(s, func(v T) T { return v })
+}
+
+// MaxDigits returns the number of decimal digits of the biggest
+// of nums, 0 if there are none.
 func MaxDigits(intArr []int) int {
-	for i := 1; i < len(intArr); i++ {
-		if intArr[i-1] > intArr[i] {
-			intArr[i-1], intArr[i] = intArr[i], intArr[i-1]
-		}
+	if len(intArr) == 0 {
+		return 0
+	}
+	maxNum := intArr[0]
+	for _, v := range intArr[1:] {
+		maxNum = max(maxNum, v)
 	}
-	maxNum := intArr[len(intArr)-1]
 	return len(strconv.Itoa(maxNum))
 }
 
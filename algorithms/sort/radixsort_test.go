@@ -0,0 +1,165 @@
+package sort_test
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+)
+
+func TestMaxDigits(t *testing.T) {
+	s := []int{905, 74, 12345, 0}
+	if n := isort.MaxDigits(s); n != 5 {
+		t.Errorf("wanted 5 digits but get %d", n)
+	}
+	if !slices.Equal(s, []int{905, 74, 12345, 0}) {
+		t.Errorf("MaxDigits changed its input to %v", s)
+	}
+	if n := isort.MaxDigits(nil); n != 0 {
+		t.Errorf("wanted no digits but get %d", n)
+	}
+}
+
+func TestRadixSortInt64(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 1000, 100000} {
+		s := make([]int64, n)
+		for i := range s {
+			s[i] = int64(rand.Uint64())
+			if i%3 == 0 {
+				s[i] >>= 40
+			}
+		}
+		if n > 2 {
+			s[0], s[1] = math.MinInt64, math.MaxInt64
+		}
+		want := slices.Clone(s)
+		slices.Sort(want)
+		isort.RadixSortInt64(s)
+		if !slices.Equal(s, want) {
+			t.Fatalf("sort of %d int64: wanted %v but get %v", n, want, s)
+		}
+	}
+}
+
+func TestRadixSortUint64(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 1000, 100000} {
+		s := make([]uint64, n)
+		for i := range s {
+			// small keys share their high bytes
+			s[i] = rand.Uint64() >> (rand.Intn(8) * 8)
+		}
+		want := slices.Clone(s)
+		slices.Sort(want)
+		isort.RadixSortUint64(s)
+		if !slices.Equal(s, want) {
+			t.Fatalf("sort of %d uint64: wanted %v but get %v", n, want, s)
+		}
+	}
+}
+
+func TestRadixSortFloat64(t *testing.T) {
+	negNaN := math.Float64frombits(math.Float64bits(math.NaN()) | 1<<63)
+	s := []float64{3.5, math.Inf(1), math.Copysign(0, -1), math.NaN(), 0, -2, math.Inf(-1), negNaN, math.SmallestNonzeroFloat64, -math.MaxFloat64, 1e300, -1e-300}
+	for i := 0; i < 1000; i++ {
+		s = append(s, rand.NormFloat64()*1e6)
+	}
+	isort.RadixSortFloat64(s)
+	if !math.IsNaN(s[0]) || !math.Signbit(s[0]) || !math.IsNaN(s[len(s)-1]) || math.Signbit(s[len(s)-1]) {
+		t.Errorf("wanted the NaNs at the ends but get %v and %v", s[0], s[len(s)-1])
+	}
+	if !math.IsInf(s[1], -1) || !math.IsInf(s[len(s)-2], 1) {
+		t.Errorf("wanted the infinities next to the NaNs but get %v and %v", s[1], s[len(s)-2])
+	}
+	if !slices.IsSorted(s[1 : len(s)-1]) {
+		t.Errorf("not sorted %v", s)
+	}
+	zero := slices.Index(s, 0)
+	if !math.Signbit(s[zero]) || math.Signbit(s[zero+1]) {
+		t.Errorf("wanted -0 before +0")
+	}
+}
+
+func TestRadixSortBy(t *testing.T) {
+	s := make([]pair, 10000)
+	for i := range s {
+		s[i] = pair{key: rand.Intn(500) - 250, pos: i}
+	}
+	want := slices.Clone(s)
+	slices.SortStableFunc(want, comparePairs)
+	isort.RadixSortBy(s, func(p pair) uint64 { return uint64(p.key) ^ 1<<63 })
+	if !slices.Equal(s, want) {
+		t.Errorf("not stable")
+	}
+}
+
+func TestRadixSortStrings(t *testing.T) {
+	words := []string{"", "a", "ab", "abc", "abd", "b", "ba", "\xff", "\x00", "\x00\x00"}
+	for _, n := range []int{0, 1, 10, 1000, 20000} {
+		s := make([]string, n)
+		for i := range s {
+			// long common prefixes and short ones
+			s[i] = strings.Repeat("prefix", rand.Intn(3)) + words[rand.Intn(len(words))] + words[rand.Intn(len(words))]
+		}
+		b := make([][]byte, n)
+		for i := range s {
+			b[i] = []byte(s[i])
+		}
+		want := slices.Clone(s)
+		slices.Sort(want)
+
+		isort.RadixSortStrings(s)
+		if !slices.Equal(s, want) {
+			t.Fatalf("sort of %d strings: wanted %q but get %q", n, want, s)
+		}
+		isort.RadixSortBytes(b)
+		if !slices.IsSortedFunc(b, bytes.Compare) {
+			t.Fatalf("sort of %d byte slices: not sorted", n)
+		}
+	}
+}
+
+func TestRadixSortStringsLongPrefixes(t *testing.T) {
+	// a deep recursion would take a frame for each byte of the prefix
+	long := strings.Repeat("x", 1<<20)
+	s := make([]string, 64)
+	for i := range s {
+		s[i] = long
+		if i%2 == 0 {
+			s[i] += string(rune('a' + i%26))
+		}
+	}
+	want := slices.Clone(s)
+	slices.Sort(want)
+	isort.RadixSortStrings(s)
+	if !slices.Equal(s, want) {
+		t.Errorf("strings with a long common prefix not sorted")
+	}
+	b := make([][]byte, 64)
+	for i := range b {
+		b[i] = []byte(long)
+	}
+	isort.RadixSortBytes(b)
+}
+
+func TestIntegerForms(t *testing.T) {
+	small := []int8{5, -128, 127, 0, -1, 5, 1}
+	large := []uint64{math.MaxUint64, 0, 1 << 63, 7, 1<<63 - 1}
+	for name, sort := range map[string]func(s []int8){"CountingSort": isort.CountingSortOrdered[int8], "RadixSort": isort.RadixSortOrdered[int8]} {
+		s := slices.Clone(small)
+		sort(s)
+		if !slices.IsSorted(s) {
+			t.Errorf("%sOrdered of int8: get %v", name, s)
+		}
+	}
+	for name, sort := range map[string]func(s []uint64){"CountingSort": isort.CountingSortOrdered[uint64], "RadixSort": isort.RadixSortOrdered[uint64]} {
+		s := slices.Clone(large)
+		sort(s)
+		if !slices.IsSorted(s) {
+			t.Errorf("%sOrdered of uint64: get %v", name, s)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package sort
+
+import (
+	"cmp"
+	"iter"
+	"math/bits"
+
+	"github.com/man-fish/goalgorithms/datastructures/heap"
+)
+
+// NthElement is a O(n) unstable selection algorithm, it reorders data so that
+// data[k] is the element which would be there if data were sorted, no element
+// before it is greater and no element after it is less. It partitions data like
+// PdqSort and keeps the side which holds k, after too many unbalanced partitions
+// it takes the median of medians as pivot so that no input takes O(n^2).
+func NthElement(data Sortable, k int) {
+	n := data.Len()
+	if k < 0 || k >= n {
+		panic("sort: NthElement index out of range")
+	}
+	nthElement(data, 0, n, k, bits.Len(uint(n)))
+}
+
+// NthElementSlice reorders s in the order of compare with NthElement
+func NthElementSlice[T any](s []T, k int, compare func(a, b T) int) {
+	NthElement(&funcSlice[T]{s: s, compare: compare}, k)
+}
+
+// NthElementOrdered reorders s in increasing order with NthElement
+func NthElementOrdered[T cmp.Ordered](s []T, k int) {
+	NthElement(orderedSlice[T](s), k)
+}
+
+// nthElement puts the element of rank k in data[a:b] at k, limit is the
+// number of unbalanced partitions allowed before the median of medians.
+func nthElement(data Sortable, a, b, k, limit int) {
+	for b-a > pdqInsertionMax {
+		length := b - a
+		var pivot int
+		if limit > 0 {
+			pivot, _ = choosePivot(data, a, b)
+		} else {
+			pivot = medianOfMedians(data, a, b)
+		}
+		// data[a-1] is the pivot of an enclosing partition, see pdqsort
+		if a > 0 && !data.Less(a-1, pivot) {
+			mid := partitionEqual(data, a, b, pivot)
+			if k < mid {
+				return
+			}
+			a = mid
+			continue
+		}
+
+		mid, _ := partition(data, a, b, pivot)
+		if min(mid-a, b-mid) < length/8 {
+			limit--
+		}
+		switch {
+		case k < mid:
+			b = mid
+		case k > mid:
+			a = mid + 1
+		default:
+			return
+		}
+	}
+	insertionSort(data, a, b)
+}
+
+// medianOfMedians returns the index of a pivot with about 3/10 of data[a:b]
+// on each side, it moves the median of each group of five elements to the
+// front of data[a:b] and selects the median of those.
+func medianOfMedians(data Sortable, a, b int) int {
+	m := a
+	for i := a; i < b; i += 5 {
+		j := min(i+5, b)
+		insertionSort(data, i, j)
+		data.Swap(m, i+(j-i)/2)
+		m++
+	}
+	mid := a + (m-a)/2
+	nthElement(data, a, m, mid, 0)
+	return mid
+}
+
+// PartialSort is a O(n+klog(k)) unstable sorting algorithm, it puts the k
+// least elements of data at its front in order and the others after them
+// in no particular order. It selects the kth element with NthElement and
+// sorts those before it with PdqSort.
+func PartialSort(data Sortable, k int) {
+	n := data.Len()
+	k = min(max(k, 0), n)
+	if k < n {
+		nthElement(data, 0, n, k, bits.Len(uint(n)))
+	}
+	pdqsort(data, 0, k, bits.Len(uint(k)))
+}
+
+// PartialSortSlice sorts the front of s in the order of compare with PartialSort
+func PartialSortSlice[T any](s []T, k int, compare func(a, b T) int) {
+	PartialSort(&funcSlice[T]{s: s, compare: compare}, k)
+}
+
+// PartialSortOrdered sorts the front of s in increasing order with PartialSort
+func PartialSortOrdered[T cmp.Ordered](s []T, k int) {
+	PartialSort(orderedSlice[T](s), k)
+}
+
+// TopK returns the k greatest elements of seq in the order of compare from the
+// greatest, it keeps them in a minimum heap.Heap as it reads seq, so that a
+// sequence of n elements takes O(nlog(k)) time and O(k) memory.
+func TopK[T any](seq iter.Seq[T], k int, compare func(a, b T) int) []T {
+	if k <= 0 {
+		return nil
+	}
+	h := heap.NewFunc(make([]T, 0, k), func(a, b T) bool { return compare(a, b) < 0 })
+	for x := range seq {
+		if h.Size() < k {
+			h.Insert(x)
+			continue
+		}
+		// the root is the least of the k greatest so far
+		if root, _ := h.Peek(); compare(root, x) < 0 {
+			h.Replace(x)
+		}
+	}
+	top := make([]T, h.Size())
+	for i := len(top) - 1; i >= 0; i-- {
+		top[i], _ = h.Pop()
+	}
+	return top
+}
+
+// TopKOrdered returns the k greatest elements of seq in decreasing order with TopK
+func TopKOrdered[T cmp.Ordered](seq iter.Seq[T], k int) []T {
+	return TopK(seq, k, cmp.Compare[T])
+}
+
+/*
+Complexity of introselect：
+	* Best: 	O(n)
+	* Average: 	O(n)
+	* Worst: 	O(n)
+	* Memory: 	O(log(n))
+	* Stable: 	No
+	* Wiki: 	https://en.wikipedia.org/wiki/Introselect
+Shortcome from wiki:
+	Introselect is a selection algorithm that is a hybrid of quickselect
+	and median of medians which has fast average performance and optimal
+	worst-case performance. Quickselect is used first and, if it makes too
+	little progress, the median of medians pivot is used instead, whose
+	partitions leave at least 30% of the elements on each side.
+*/
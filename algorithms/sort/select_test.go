@@ -0,0 +1,102 @@
+package sort_test
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+)
+
+// checkNth fails unless s[k] is want[k] with nothing greater before it and nothing less after it
+func checkNth(t *testing.T, name string, s, want []int, k int) {
+	t.Helper()
+	if s[k] != want[k] {
+		t.Fatalf("%s: wanted %d at %d but get %d", name, want[k], k, s[k])
+	}
+	for i, v := range s {
+		if (i < k && v > s[k]) || (i > k && v < s[k]) {
+			t.Fatalf("%s: %d at %d is on the wrong side of %d", name, v, i, k)
+		}
+	}
+}
+
+func TestNthElement(t *testing.T) {
+	for _, n := range []int{1, 2, 13, 100, 1001} {
+		src := []int(isort.RandomArray(n, n/2+1))
+		want := slices.Sorted(slices.Values(src))
+		for k := 0; k < n; k += n/20 + 1 {
+			s := slices.Clone(src)
+			isort.NthElementOrdered(s, k)
+			checkNth(t, "random", s, want, k)
+		}
+	}
+}
+
+func TestNthElementKillers(t *testing.T) {
+	for name, gen := range killers {
+		for _, n := range []int{100, 1001, 10000} {
+			want := slices.Sorted(slices.Values(gen(n)))
+			for _, k := range []int{0, n / 10, n / 2, n - 1} {
+				// a linear bound
+				d := &countingInts{t: t, data: gen(n), max: 40*n + 100}
+				isort.NthElement(d, k)
+				checkNth(t, name, d.data, want, k)
+			}
+		}
+	}
+}
+
+func TestNthElementAdversary(t *testing.T) {
+	for _, n := range []int{100, 1000, 10000} {
+		d := newAdversary(t, n)
+		d.max = 40*n + 100
+		isort.NthElement(d, n/2)
+		for i, v := range d.data {
+			if (i < n/2 && v > d.data[n/2]) || (i > n/2 && v < d.data[n/2]) {
+				t.Fatalf("adversary of %d: %d at %d is on the wrong side", n, v, i)
+			}
+		}
+	}
+}
+
+func TestPartialSort(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 10000} {
+		// distinct keys, as PartialSort is not stable
+		src := make([]pair, n)
+		for i, key := range rand.Perm(n) {
+			src[i] = pair{key: key, pos: i}
+		}
+		want := slices.SortedFunc(slices.Values(src), comparePairs)
+		for _, k := range []int{-1, 0, 1, n / 3, n, n + 1} {
+			s := slices.Clone(src)
+			isort.PartialSortSlice(s, k, comparePairs)
+			k = min(max(k, 0), n)
+			if !slices.Equal(s[:k], want[:k]) {
+				t.Fatalf("%d of %d: wanted %v but get %v", k, n, want[:k], s[:k])
+			}
+			rest := slices.SortedFunc(slices.Values(s[k:]), comparePairs)
+			if !slices.Equal(rest, want[k:]) {
+				t.Fatalf("%d of %d: lost elements after the front", k, n)
+			}
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	src := []int(isort.RandomArray(10000, 500))
+	want := slices.Sorted(slices.Values(src))
+	slices.Reverse(want)
+	for _, k := range []int{0, 1, 10, 10000, 20000} {
+		top := isort.TopKOrdered(slices.Values(src), k)
+		if wk := want[:min(k, len(want))]; !slices.Equal(top, wk) {
+			t.Fatalf("top %d: wanted %v but get %v", k, wk, top)
+		}
+	}
+	// the k least with the reverse order
+	least := isort.TopK(slices.Values(src), 5, func(a, b int) int { return cmp.Compare(b, a) })
+	if wk := slices.Sorted(slices.Values(src))[:5]; !slices.Equal(least, wk) {
+		t.Errorf("least 5: wanted %v but get %v", wk, least)
+	}
+}
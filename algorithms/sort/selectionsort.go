@@ -1,5 +1,7 @@
 package sort
 
+import "cmp"
+
 // SelectSort is the worst
 func SelectSort(data Sortable) {
 	for i := 0; i < data.Len(); i++ {
@@ -13,6 +15,16 @@ func SelectSort(data Sortable) {
 	}
 }
 
+// SelectSortSlice sorts s in the order of compare with SelectSort
+func SelectSortSlice[T any](s []T, compare func(a, b T) int) {
+	SelectSort(&funcSlice[T]{s: s, compare: compare})
+}
+
+// SelectSortOrdered sorts s in increasing order with SelectSort
+func SelectSortOrdered[T cmp.Ordered](s []T) {
+	SelectSort(orderedSlice[T](s))
+}
+
 /*
 Complexity of select sort：
 	* Best: 	O(n^2)
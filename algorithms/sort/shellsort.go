@@ -1,5 +1,7 @@
 package sort
 
+import "cmp"
+
 // ShellSort is a unstable sorting algorithm
 func ShellSort(data Sortable) {
 	for dalta := data.Len() / 2; dalta > 0; dalta = dalta >> 1 {
@@ -11,6 +13,16 @@ func ShellSort(data Sortable) {
 	}
 }
 
+// ShellSortSlice sorts s in the order of compare with ShellSort
+func ShellSortSlice[T any](s []T, compare func(a, b T) int) {
+	ShellSort(&funcSlice[T]{s: s, compare: compare})
+}
+
+// ShellSortOrdered sorts s in increasing order with ShellSort
+func ShellSortOrdered[T cmp.Ordered](s []T) {
+	ShellSort(orderedSlice[T](s))
+}
+
 /*
 Complexity of shell sort：
 	* Best: 	O(nlog(n))
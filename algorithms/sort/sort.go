@@ -1,6 +1,7 @@
 package sort
 
 import (
+	"cmp"
 	"math/rand"
 )
 
@@ -27,6 +28,62 @@ func (p IntSlice) Swap(i, j int)       { p[i], p[j] = p[j], p[i] }
 
 var _ Sortable = (*IntSlice)(nil)
 
+// funcSlice attaches the methods of Sortable to []T, sorting in the order of
+// compare, which returns a negative number if a is before b, a positive number
+// if a is after b and zero if they are equal. It backs the XxxSlice sorts.
+type funcSlice[T any] struct {
+	s       []T
+	compare func(a, b T) int
+}
+
+func (p *funcSlice[T]) Len() int            { return len(p.s) }
+func (p *funcSlice[T]) Less(i, j int) bool  { return p.compare(p.s[i], p.s[j]) < 0 }
+func (p *funcSlice[T]) Equal(i, j int) bool { return p.compare(p.s[i], p.s[j]) == 0 }
+func (p *funcSlice[T]) Swap(i, j int)       { p.s[i], p.s[j] = p.s[j], p.s[i] }
+
+var _ Sortable = (*funcSlice[int])(nil)
+
+// orderedSlice attaches the methods of Sortable to []T, sorting in increasing
+// order with NaNs first like cmp.Compare. It backs the XxxOrdered sorts.
+type orderedSlice[T cmp.Ordered] []T
+
+func (p orderedSlice[T]) Len() int            { return len(p) }
+func (p orderedSlice[T]) Less(i, j int) bool  { return cmp.Less(p[i], p[j]) }
+func (p orderedSlice[T]) Equal(i, j int) bool { return cmp.Compare(p[i], p[j]) == 0 }
+func (p orderedSlice[T]) Swap(i, j int)       { p[i], p[j] = p[j], p[i] }
+
+var _ Sortable = (*orderedSlice[int])(nil)
+
+// Integer is the constraint of the integer types, the keys of the
+// sorts which count keys instead of comparing elements.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// integerBits maps k to a uint64 in the same order as the keys of K,
+// the sign bit of a signed key is flipped so that negative keys map first.
+func integerBits[K Integer](k K) uint64 {
+	if K(0)-1 < 0 {
+		return uint64(k) ^ 1<<63
+	}
+	return uint64(k)
+}
+
+// permute moves the element at index perm[k] of data to index k
+// following the cycles of perm, which is left as the identity.
+func permute(data Sortable, perm []int) {
+	for k := range perm {
+		j := k
+		for perm[j] != k {
+			next := perm[j]
+			data.Swap(j, next)
+			perm[j] = j
+			j = next
+		}
+		perm[j] = j
+	}
+}
+
 // RandomArray generate a random arr
 func RandomArray(length, max int) IntSlice {
 	arr := make([]int, length)
@@ -1,6 +1,10 @@
 package sort_test
 
 import (
+	"cmp"
+	"math"
+	"math/rand"
+	"slices"
 	"sort"
 	"testing"
 
@@ -30,11 +34,12 @@ func TestCountingSort(t *testing.T) {
 }
 
 func TestHeapSort(t *testing.T) {
-	sorted := isort.HeapSort(ints[0:])
-	sortedSlice := isort.IntSlice(sorted)
-	if !sort.IsSorted(sortedSlice) {
-		t.Errorf("sorted %v", positiveInts)
-		t.Errorf(" goted %v", sorted)
+	data := ints
+	a := isort.IntSlice(data[0:])
+	isort.HeapSort(a)
+	if !sort.IsSorted(a) {
+		t.Errorf("sorted %v", ints)
+		t.Errorf(" goted %v", data)
 	}
 }
 
@@ -96,3 +101,67 @@ func TestShellSort(t *testing.T) {
 		t.Errorf(" goted %v", data)
 	}
 }
+
+// algorithm is a sort in its three forms
+type algorithm struct {
+	name    string
+	stable  bool
+	data    func(data isort.Sortable)
+	slice   func(s []pair, compare func(a, b pair) int)
+	ordered func(s []float64)
+}
+
+var algorithms = []algorithm{
+	{"BubbleSort", true, isort.BubbleSort, isort.BubbleSortSlice[pair], isort.BubbleSortOrdered[float64]},
+	{"HeapSort", false, isort.HeapSort, isort.HeapSortSlice[pair], isort.HeapSortOrdered[float64]},
+	{"InPlaceMergeSort", true, isort.InPlaceMergeSort, isort.InPlaceMergeSortSlice[pair], isort.InPlaceMergeSortOrdered[float64]},
+	{"InsertionSort", true, isort.InsertionSort, isort.InsertionSortSlice[pair], isort.InsertionSortOrdered[float64]},
+	{"MergeSort", true, isort.MergeSort, isort.MergeSortSlice[pair], isort.MergeSortOrdered[float64]},
+	{"PdqSort", false, isort.PdqSort, isort.PdqSortSlice[pair], isort.PdqSortOrdered[float64]},
+	{"QuickSort", false, isort.QuickSort, isort.QuickSortSlice[pair], isort.QuickSortOrdered[float64]},
+	{"SelectSort", false, isort.SelectSort, isort.SelectSortSlice[pair], isort.SelectSortOrdered[float64]},
+	{"ShellSort", false, isort.ShellSort, isort.ShellSortSlice[pair], isort.ShellSortOrdered[float64]},
+	{"TimSort", true, isort.TimSort, isort.TimSortSlice[pair], isort.TimSortOrdered[float64]},
+}
+
+// pair is a key with its position in the input to check stability
+type pair struct {
+	key, pos int
+}
+
+func comparePairs(a, b pair) int { return cmp.Compare(a.key, b.key) }
+
+func TestSortForms(t *testing.T) {
+	for _, alg := range algorithms {
+		for _, n := range []int{0, 1, 2, 3, 10, 100, 1000} {
+			a := isort.RandomArray(n, 50)
+			alg.data(a)
+			if !sort.IsSorted(a) {
+				t.Errorf("%s of %d ints: got %v", alg.name, n, a)
+			}
+
+			pairs := make([]pair, n)
+			for i := range pairs {
+				pairs[i] = pair{key: rand.Intn(n/4 + 1), pos: i}
+			}
+			alg.slice(pairs, comparePairs)
+			for i := 1; i < n; i++ {
+				if pairs[i-1].key > pairs[i].key || alg.stable && pairs[i-1].key == pairs[i].key && pairs[i-1].pos > pairs[i].pos {
+					t.Fatalf("%sSlice of %d pairs: %v before %v", alg.name, n, pairs[i-1], pairs[i])
+				}
+			}
+
+			floats := make([]float64, n)
+			for i := range floats {
+				floats[i] = rand.NormFloat64()
+			}
+			if n > 2 {
+				floats[n/2] = math.NaN()
+			}
+			alg.ordered(floats)
+			if !slices.IsSortedFunc(floats, cmp.Compare[float64]) {
+				t.Errorf("%sOrdered of %d floats: got %v", alg.name, n, floats)
+			}
+		}
+	}
+}
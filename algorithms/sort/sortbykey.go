@@ -0,0 +1,124 @@
+package sort
+
+import "cmp"
+
+// SortByKey is a O(nlog(n)) unstable sorting algorithm which sorts data in the
+// increasing order of key, it calls key once for each element instead of twice
+// for each comparison. It sorts the keys paired with the indexes of their
+// elements, by RadixSortBy if K is a predeclared integer type and by PdqSort
+// otherwise, and then moves each element of data to its place following the
+// cycles.
+func SortByKey[T any, K cmp.Ordered](data []T, key func(T) K) {
+	sortByKey(data, key, false)
+}
+
+// StableSortByKey sorts data like SortByKey, the elements with equal
+// keys keep their order, it sorts the keys by TimSort if K is not a
+// predeclared integer type.
+func StableSortByKey[T any, K cmp.Ordered](data []T, key func(T) K) {
+	sortByKey(data, key, true)
+}
+
+// keyed is a key paired with the index of its element
+type keyed[K cmp.Ordered] struct {
+	key K
+	idx int
+}
+
+func sortByKey[T any, K cmp.Ordered](data []T, key func(T) K, stable bool) {
+	if len(data) < 2 {
+		return
+	}
+	keys := make([]K, len(data))
+	for i, v := range data {
+		keys[i] = key(v)
+	}
+	perm := make([]int, len(data))
+	if ukeys, ok := integerKeys(keys); ok {
+		// the radix sort is stable either way
+		for i := range perm {
+			perm[i] = i
+		}
+		RadixSortBy(perm, func(i int) uint64 { return ukeys[i] })
+	} else {
+		pairs := make([]keyed[K], len(data))
+		for i, k := range keys {
+			pairs[i] = keyed[K]{key: k, idx: i}
+		}
+		if stable {
+			timSort(pairs, func(a, b keyed[K]) bool { return cmp.Less(a.key, b.key) })
+		} else {
+			PdqSortSlice(pairs, func(a, b keyed[K]) int { return cmp.Compare(a.key, b.key) })
+		}
+		for i, p := range pairs {
+			perm[i] = p.idx
+		}
+	}
+	// permute only swaps, the slice needs no compare
+	permute(&funcSlice[T]{s: data}, perm)
+}
+
+// integerKeys maps keys of an integer type to uint64s in the same order,
+// ok is false for the other types.
+func integerKeys[K cmp.Ordered](keys []K) (ukeys []uint64, ok bool) {
+	switch keys := any(keys).(type) {
+	case []int:
+		return signedKeys(keys), true
+	case []int8:
+		return signedKeys(keys), true
+	case []int16:
+		return signedKeys(keys), true
+	case []int32:
+		return signedKeys(keys), true
+	case []int64:
+		return signedKeys(keys), true
+	case []uint:
+		return unsignedKeys(keys), true
+	case []uint8:
+		return unsignedKeys(keys), true
+	case []uint16:
+		return unsignedKeys(keys), true
+	case []uint32:
+		return unsignedKeys(keys), true
+	case []uint64:
+		return keys, true
+	case []uintptr:
+		return unsignedKeys(keys), true
+	}
+	return nil, false
+}
+
+// signedKeys flips the sign bits so that the negative keys map before the others
+func signedKeys[S ~int | ~int8 | ~int16 | ~int32 | ~int64](keys []S) []uint64 {
+	ukeys := make([]uint64, len(keys))
+	for i, k := range keys {
+		ukeys[i] = uint64(int64(k)) ^ 1<<63
+	}
+	return ukeys
+}
+
+func unsignedKeys[U ~uint | ~uint8 | ~uint16 | ~uint32 | ~uintptr](keys []U) []uint64 {
+	ukeys := make([]uint64, len(keys))
+	for i, k := range keys {
+		ukeys[i] = uint64(k)
+	}
+	return ukeys
+}
+
+/*
+Complexity of sort by key：
+	* Best: 	O(n)
+	* Average: 	O(nlog(n))
+	* Worst: 	O(nlog(n))
+	* Memory: 	O(n)
+	* Stable: 	StableSortByKey only
+	* Wiki: 	https://en.wikipedia.org/wiki/Schwartzian_transform
+Shortcome from wiki:
+	In computer programming, the Schwartzian transform is a technique used
+	to improve the efficiency of sorting a list of items. This idiom is
+	appropriate for comparison-based sorting when the ordering is actually
+	based on the ordering of a certain property (the key) of the elements,
+	where computing that property is an intensive operation that should be
+	performed a minimal number of times. It is also known as
+	decorate-sort-undecorate.
+*/
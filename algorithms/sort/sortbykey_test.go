@@ -0,0 +1,93 @@
+package sort_test
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+)
+
+type event struct {
+	stamp string
+	pos   int
+}
+
+func TestSortByKey(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := make([]event, 5000)
+	for i := range src {
+		stamp := base.Add(time.Duration(rand.Intn(300)-150) * time.Hour)
+		src[i] = event{stamp: stamp.Format(time.RFC3339), pos: i}
+	}
+	calls := 0
+	parse := func(e event) time.Time {
+		calls++
+		ts, _ := time.Parse(time.RFC3339, e.stamp)
+		return ts
+	}
+	compare := func(a, b event) int { return parse(a).Compare(parse(b)) }
+	want := slices.Clone(src)
+	slices.SortStableFunc(want, compare)
+
+	// integer keys
+	s := slices.Clone(src)
+	calls = 0
+	isort.StableSortByKey(s, func(e event) int64 { return parse(e).Unix() })
+	if !slices.Equal(s, want) || calls != len(s) {
+		t.Errorf("int64 keys: stable %v with %d calls to key", slices.Equal(s, want), calls)
+	}
+	s = slices.Clone(src)
+	isort.SortByKey(s, func(e event) int64 { return parse(e).Unix() })
+	if !slices.IsSortedFunc(s, compare) {
+		t.Errorf("int64 keys: not sorted")
+	}
+
+	// string keys
+	s = slices.Clone(src)
+	isort.StableSortByKey(s, func(e event) string { return e.stamp })
+	if !slices.Equal(s, want) {
+		t.Errorf("string keys: not stable")
+	}
+	s = slices.Clone(src)
+	isort.SortByKey(s, func(e event) string { return e.stamp })
+	if !slices.IsSortedFunc(s, compare) {
+		t.Errorf("string keys: not sorted")
+	}
+}
+
+func TestSortByKeyTypes(t *testing.T) {
+	src := []int{math.MinInt, -300, -1, 0, 1, 255, 256, 70000, math.MaxInt}
+	rand.Shuffle(len(src), func(i, j int) { src[i], src[j] = src[j], src[i] })
+	sorted := func(name string, s []int) {
+		t.Helper()
+		if !slices.IsSorted(s) {
+			t.Errorf("%s keys: not sorted %v", name, s)
+		}
+	}
+	s := slices.Clone(src)
+	isort.SortByKey(s, func(v int) int { return v })
+	sorted("int", s)
+	s = slices.Clone(src)
+	isort.SortByKey(s, func(v int) float64 { return float64(v) })
+	sorted("float64", s)
+	s = slices.Clone(src)
+	isort.SortByKey(s, func(v int) uint64 { return uint64(v) ^ 1<<63 })
+	sorted("uint64", s)
+	// an int8 key sorts by the low byte
+	s = []int{0x102, 0x201, -1, 0x7f}
+	isort.StableSortByKey(s, func(v int) int8 { return int8(v) })
+	if want := []int{-1, 0x201, 0x102, 0x7f}; !slices.Equal(s, want) {
+		t.Errorf("int8 keys: wanted %v but get %v", want, s)
+	}
+	// named key types sort by comparison
+	type level string
+	words := []string{"b", "c", "a"}
+	isort.SortByKey(words, func(w string) level { return level(w) })
+	if !slices.IsSortedFunc(words, cmp.Compare[string]) {
+		t.Errorf("named keys: not sorted %v", words)
+	}
+}
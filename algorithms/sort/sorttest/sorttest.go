@@ -0,0 +1,288 @@
+/*
+Package sorttest checks sorting algorithms by their properties instead of
+by fixed outputs. It runs a sort over inputs of many lengths and
+distributions, or over the inputs of a fuzz target, and checks that:
+
+  - the output is sorted,
+  - the output is a permutation of the input,
+  - elements with equal keys keep their order, if the sort is stable,
+  - the input is left unchanged, if the sort returns a new slice.
+
+A sort is adapted to the harness as a Sorter, which sorts Items by Key:
+
+	func TestPdqSort(t *testing.T) {
+		sorttest.Run(t, sorttest.FromSortable("PdqSort", false, sort.PdqSort))
+	}
+
+	func FuzzPdqSort(f *testing.F) {
+		sorttest.Fuzz(f,
+			sorttest.FromSortable("PdqSort", false, sort.PdqSort),
+			sorttest.FromSlice("PdqSortSlice", false, sort.PdqSortSlice[sorttest.Item]),
+		)
+	}
+*/
+package sorttest
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+	"github.com/man-fish/goalgorithms/algorithms/sort/trace"
+)
+
+// Item is an element of an input, sorts order items by Key and
+// Pos is the index of the item in the input.
+type Item struct {
+	Key, Pos int
+}
+
+// compareItems orders items by Key only
+func compareItems(a, b Item) int {
+	switch {
+	case a.Key < b.Key:
+		return -1
+	case a.Key > b.Key:
+		return 1
+	}
+	return 0
+}
+
+// Sorter adapts a sort to the harness
+type Sorter struct {
+	Name string
+	// Sort sorts items by Key and returns them, a sort in place
+	// returns its argument and a sort to a new slice returns that.
+	Sort func(items []Item) []Item
+	// Stable is set if items with equal keys must keep their order
+	Stable bool
+	// KeysOnly is set if Sort sorts the keys without their items,
+	// the positions of its output are not checked.
+	KeysOnly bool
+	// NonMutating is set if Sort must leave its argument unchanged
+	NonMutating bool
+	// MinKey and MaxKey bound the keys of the inputs if MaxKey > MinKey,
+	// the keys of other inputs are wrapped into the range.
+	MinKey, MaxKey int
+	// MaxLen bounds the length of the inputs of Run if not 0
+	MaxLen int
+	// ValidLen reports whether Sort accepts an input of length n, nil accepts all
+	ValidLen func(n int) bool
+}
+
+// items attaches the methods of sort.Sortable to a slice of items
+type items []Item
+
+func (s items) Len() int            { return len(s) }
+func (s items) Less(i, j int) bool  { return s[i].Key < s[j].Key }
+func (s items) Equal(i, j int) bool { return s[i].Key == s[j].Key }
+func (s items) Swap(i, j int)       { s[i], s[j] = s[j], s[i] }
+
+// FromSortable adapts a sort of the Sortable form
+func FromSortable(name string, stable bool, sort func(data isort.Sortable)) Sorter {
+	return Sorter{Name: name, Stable: stable, Sort: func(s []Item) []Item {
+		sort(items(s))
+		return s
+	}}
+}
+
+// FromSlice adapts a sort of the Slice form
+func FromSlice(name string, stable bool, sort func(s []Item, compare func(a, b Item) int)) Sorter {
+	return Sorter{Name: name, Stable: stable, Sort: func(s []Item) []Item {
+		sort(s, compareItems)
+		return s
+	}}
+}
+
+// FromInts adapts a sort of the keys, which returns them sorted in
+// place if inPlace is set and in a new slice otherwise.
+func FromInts(name string, inPlace bool, sort func(keys []int) []int) Sorter {
+	return Sorter{Name: name, KeysOnly: true, NonMutating: !inPlace, Sort: func(s []Item) []Item {
+		keys := make([]int, len(s))
+		for i, it := range s {
+			keys[i] = it.Key
+		}
+		sorted := sort(keys)
+		// the changes to keys show in s, so that a mutation is caught
+		for i, k := range keys {
+			s[i].Key = k
+		}
+		out := make([]Item, len(sorted))
+		for i, k := range sorted {
+			out[i] = Item{Key: k, Pos: -1}
+		}
+		return out
+	}}
+}
+
+// FromFloats adapts a sort of the keys as float64s in place, the
+// keys are bounded to ±2^52 so that they convert exactly.
+func FromFloats(name string, sort func(keys []float64)) Sorter {
+	s := FromInts(name, true, func(keys []int) []int {
+		floats := make([]float64, len(keys))
+		for i, k := range keys {
+			floats[i] = float64(k)
+		}
+		sort(floats)
+		for i, f := range floats {
+			keys[i] = int(f)
+		}
+		return keys
+	})
+	s.MinKey, s.MaxKey = -1<<52, 1<<52
+	return s
+}
+
+// Distribution is a kind of input, the same as in the trace package
+type Distribution = trace.Distribution
+
+// Distributions are the inputs of Run, the ones of the trace package
+// and the patterns which are known to defeat sorts.
+var Distributions = append(slices.Clip(trace.Distributions), []Distribution{
+	{Name: "signed", Gen: func(r *rand.Rand, n int) []int {
+		keys := make([]int, n)
+		for i := range keys {
+			keys[i] = r.Int() - r.Int()
+		}
+		return keys
+	}},
+	{Name: "equal", Gen: func(r *rand.Rand, n int) []int {
+		return make([]int, n)
+	}},
+	{Name: "sawtooth", Gen: func(r *rand.Rand, n int) []int {
+		keys := make([]int, n)
+		for i := range keys {
+			keys[i] = i % 17
+		}
+		return keys
+	}},
+	{Name: "nearly-sorted", Gen: func(r *rand.Rand, n int) []int {
+		keys := make([]int, n)
+		for i := range keys {
+			keys[i] = i
+		}
+		for k := 0; k < n/20+1 && n > 1; k++ {
+			i, j := r.Intn(n), r.Intn(n)
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+		return keys
+	}},
+	{Name: "extremes", Gen: func(r *rand.Rand, n int) []int {
+		ends := []int{math.MinInt, math.MinInt + 1, -1, 0, 1, math.MaxInt - 1, math.MaxInt}
+		keys := make([]int, n)
+		for i := range keys {
+			keys[i] = ends[r.Intn(len(ends))]
+		}
+		return keys
+	}},
+}...)
+
+// Sizes are the lengths of the inputs of Run, around the
+// thresholds at which sorts change their strategy.
+var Sizes = []int{0, 1, 2, 3, 4, 5, 7, 8, 12, 13, 16, 20, 31, 32, 33, 50, 64, 100, 128, 1000, 4096, 10000}
+
+// Run checks s over inputs of each of Distributions and Sizes
+func Run(t *testing.T, s Sorter) {
+	r := rand.New(rand.NewSource(1))
+	for _, dist := range Distributions {
+		t.Run(s.Name+"/"+dist.Name, func(t *testing.T) {
+			for _, n := range Sizes {
+				if s.MaxLen > 0 && n > s.MaxLen {
+					break
+				}
+				keys := dist.Gen(r, n)
+				in := make([]Item, n)
+				for i, k := range keys {
+					in[i] = Item{Key: k}
+				}
+				Check(t, s, in)
+			}
+		})
+	}
+}
+
+// Fuzz registers a fuzz target of sorters on f, the bytes of the
+// fuzzed inputs are the keys of the items, so that they repeat often.
+func Fuzz(f *testing.F, sorters ...Sorter) {
+	f.Add([]byte{})
+	f.Add([]byte{3, 1, 2})
+	f.Add([]byte("the quick brown fox jumps over the lazy dog"))
+	f.Add([]byte{255, 0, 128, 127, 1, 254, 0, 0, 255})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		in := make([]Item, len(data))
+		for i, b := range data {
+			in[i] = Item{Key: int(int8(b))}
+		}
+		for _, s := range sorters {
+			Check(t, s, in)
+		}
+	})
+}
+
+// Check sorts in with s and fails t unless the output has the properties of s,
+// the keys of in are wrapped into the range of s and the positions are set.
+func Check(t testing.TB, s Sorter, in []Item) {
+	t.Helper()
+	if s.ValidLen != nil && !s.ValidLen(len(in)) {
+		return
+	}
+	in = slices.Clone(in)
+	for i := range in {
+		in[i].Pos = i
+		if s.MaxKey > s.MinKey {
+			span := uint(s.MaxKey-s.MinKey) + 1
+			if span != 0 {
+				in[i].Key = s.MinKey + int(uint(in[i].Key-s.MinKey)%span)
+			}
+		}
+	}
+	arg := slices.Clone(in)
+	out := s.Sort(arg)
+
+	if s.NonMutating && !slices.Equal(arg, in) {
+		t.Fatalf("%s of %d: changed its input to %v", s.Name, len(in), short(arg))
+	}
+	if len(out) != len(in) {
+		t.Fatalf("%s of %d: returned %d elements", s.Name, len(in), len(out))
+	}
+	for i := 1; i < len(out); i++ {
+		if out[i].Key < out[i-1].Key {
+			t.Fatalf("%s of %d: %d before %d at %d in %v", s.Name, len(in), out[i-1].Key, out[i].Key, i, short(out))
+		}
+	}
+	if s.KeysOnly {
+		want := make([]int, len(in))
+		for i, it := range in {
+			want[i] = it.Key
+		}
+		slices.Sort(want)
+		for i, it := range out {
+			if it.Key != want[i] {
+				t.Fatalf("%s of %d: wanted %d at %d but get %d, not a permutation", s.Name, len(in), want[i], i, it.Key)
+			}
+		}
+		return
+	}
+	// every position once, with the key it had
+	seen := make([]bool, len(in))
+	for _, it := range out {
+		if it.Pos < 0 || it.Pos >= len(in) || seen[it.Pos] || in[it.Pos] != it {
+			t.Fatalf("%s of %d: %v is not an element of the input or is repeated", s.Name, len(in), it)
+		}
+		seen[it.Pos] = true
+	}
+	if s.Stable {
+		for i := 1; i < len(out); i++ {
+			if out[i].Key == out[i-1].Key && out[i].Pos < out[i-1].Pos {
+				t.Fatalf("%s of %d: not stable, %v before %v", s.Name, len(in), out[i-1], out[i])
+			}
+		}
+	}
+}
+
+// short returns at most the first 64 items of s, for messages
+func short[T any](s []T) []T {
+	return s[:min(len(s), 64)]
+}
@@ -0,0 +1,462 @@
+package sort
+
+import "cmp"
+
+const (
+	// timMinMerge is the shortest slice TimSort merges runs in,
+	// shorter slices are sorted with binary insertion sort.
+	timMinMerge = 32
+	// timMinGallop is the number of consecutive wins of a run
+	// after which a merge starts galloping.
+	timMinGallop = 7
+)
+
+// TimSort is a O(nlog(n)) stable sorting algorithm, it merges the natural
+// runs of data so that presorted inputs take O(n). Like MergeSort it sorts
+// the indexes of data and then moves each element to its place once.
+func TimSort(data Sortable) {
+	idx := make([]int, data.Len())
+	for i := range idx {
+		idx[i] = i
+	}
+	timSort(idx, data.Less)
+	permute(data, idx)
+}
+
+// TimSortSlice sorts s in the order of compare with TimSort
+func TimSortSlice[T any](s []T, compare func(a, b T) int) {
+	timSort(s, func(a, b T) bool { return compare(a, b) < 0 })
+}
+
+// TimSortOrdered sorts s in increasing order with TimSort
+func TimSortOrdered[T cmp.Ordered](s []T) {
+	timSort(s, cmp.Less[T])
+}
+
+// timSorter holds the pending runs of a TimSort
+type timSorter[T any] struct {
+	s    []T
+	less func(a, b T) bool
+	// minGallop adapts to how well galloping pays off
+	minGallop int
+	tmp       []T
+	// runBase and runLen are the stack of runs waiting to be merged
+	runBase, runLen []int
+}
+
+func timSort[T any](s []T, less func(a, b T) bool) {
+	n := len(s)
+	if n < 2 {
+		return
+	}
+	if n < timMinMerge {
+		binaryInsertionSort(s, 0, n, countRun(s, 0, n, less), less)
+		return
+	}
+
+	ts := &timSorter[T]{s: s, less: less, minGallop: timMinGallop}
+	minRun := minRunLength(n)
+	for lo := 0; lo < n; {
+		run := countRun(s, lo, n, less)
+		// extend short runs to minRun elements
+		if run < minRun {
+			force := min(n-lo, minRun)
+			binaryInsertionSort(s, lo, lo+force, lo+run, less)
+			run = force
+		}
+		ts.runBase = append(ts.runBase, lo)
+		ts.runLen = append(ts.runLen, run)
+		ts.mergeCollapse()
+		lo += run
+	}
+	for len(ts.runLen) > 1 {
+		i := len(ts.runLen) - 2
+		if i > 0 && ts.runLen[i-1] < ts.runLen[i+1] {
+			i--
+		}
+		ts.mergeAt(i)
+	}
+}
+
+// minRunLength returns a run length between timMinMerge/2 and timMinMerge
+// so that n/minRun is a power of two or a little less than one.
+func minRunLength(n int) int {
+	r := 0
+	for n >= timMinMerge {
+		r |= n & 1
+		n >>= 1
+	}
+	return n + r
+}
+
+// countRun returns the length of the run at s[lo:hi], a strictly
+// descending run is reversed so that equal elements keep their order.
+func countRun[T any](s []T, lo, hi int, less func(a, b T) bool) int {
+	i := lo + 1
+	if i == hi {
+		return 1
+	}
+	if less(s[i], s[lo]) {
+		for i++; i < hi && less(s[i], s[i-1]); i++ {
+		}
+		for a, b := lo, i-1; a < b; a, b = a+1, b-1 {
+			s[a], s[b] = s[b], s[a]
+		}
+	} else {
+		for i++; i < hi && !less(s[i], s[i-1]); i++ {
+		}
+	}
+	return i - lo
+}
+
+// binaryInsertionSort sorts s[lo:hi] whose first elements up to start are sorted
+func binaryInsertionSort[T any](s []T, lo, hi, start int, less func(a, b T) bool) {
+	for ; start < hi; start++ {
+		pivot := s[start]
+		// the pivot goes after the elements it is not less than
+		left, right := lo, start
+		for left < right {
+			mid := int(uint(left+right) >> 1)
+			if less(pivot, s[mid]) {
+				right = mid
+			} else {
+				left = mid + 1
+			}
+		}
+		copy(s[left+1:start+1], s[left:start])
+		s[left] = pivot
+	}
+}
+
+// mergeCollapse merges the runs on top of the stack until the lengths
+// of the runs grow faster than the Fibonacci numbers from top to bottom.
+func (ts *timSorter[T]) mergeCollapse() {
+	for len(ts.runLen) > 1 {
+		n := len(ts.runLen) - 2
+		l := ts.runLen
+		if n > 0 && l[n-1] <= l[n]+l[n+1] || n > 1 && l[n-2] <= l[n-1]+l[n] {
+			if l[n-1] < l[n+1] {
+				n--
+			}
+		} else if l[n] > l[n+1] {
+			return
+		}
+		ts.mergeAt(n)
+	}
+}
+
+// mergeAt merges the runs at i and i+1 of the stack
+func (ts *timSorter[T]) mergeAt(i int) {
+	s, less := ts.s, ts.less
+	base1, len1 := ts.runBase[i], ts.runLen[i]
+	base2, len2 := ts.runBase[i+1], ts.runLen[i+1]
+	ts.runLen[i] = len1 + len2
+	if i == len(ts.runLen)-3 {
+		ts.runBase[i+1], ts.runLen[i+1] = ts.runBase[i+2], ts.runLen[i+2]
+	}
+	ts.runBase = ts.runBase[:len(ts.runBase)-1]
+	ts.runLen = ts.runLen[:len(ts.runLen)-1]
+
+	// the elements of run1 not greater than the first one of run2
+	// and those of run2 not less than the last one of run1 stay put
+	k := gallopRight(s[base2], s[base1:base1+len1], 0, less)
+	base1 += k
+	len1 -= k
+	if len1 == 0 {
+		return
+	}
+	len2 = gallopLeft(s[base1+len1-1], s[base2:base2+len2], len2-1, less)
+	if len2 == 0 {
+		return
+	}
+	if len1 <= len2 {
+		ts.mergeLo(base1, len1, base2, len2)
+	} else {
+		ts.mergeHi(base1, len1, base2, len2)
+	}
+}
+
+// gallopLeft returns the index of a where key goes before the elements equal
+// to it, it searches from hint by exponential steps and then by bisection.
+func gallopLeft[T any](key T, a []T, hint int, less func(a, b T) bool) int {
+	lastOfs, ofs := 0, 1
+	if less(a[hint], key) {
+		// a[hint+lastOfs] < key <= a[hint+ofs]
+		maxOfs := len(a) - hint
+		for ofs < maxOfs && less(a[hint+ofs], key) {
+			lastOfs, ofs = ofs, ofs<<1+1
+		}
+		ofs = min(ofs, maxOfs)
+		lastOfs, ofs = hint+lastOfs, hint+ofs
+	} else {
+		// a[hint-ofs] < key <= a[hint-lastOfs]
+		maxOfs := hint + 1
+		for ofs < maxOfs && !less(a[hint-ofs], key) {
+			lastOfs, ofs = ofs, ofs<<1+1
+		}
+		ofs = min(ofs, maxOfs)
+		lastOfs, ofs = hint-ofs, hint-lastOfs
+	}
+	for lastOfs++; lastOfs < ofs; {
+		m := lastOfs + (ofs-lastOfs)>>1
+		if less(a[m], key) {
+			lastOfs = m + 1
+		} else {
+			ofs = m
+		}
+	}
+	return ofs
+}
+
+// gallopRight is like gallopLeft but key goes after the elements equal to it
+func gallopRight[T any](key T, a []T, hint int, less func(a, b T) bool) int {
+	lastOfs, ofs := 0, 1
+	if less(key, a[hint]) {
+		// a[hint-ofs] <= key < a[hint-lastOfs]
+		maxOfs := hint + 1
+		for ofs < maxOfs && less(key, a[hint-ofs]) {
+			lastOfs, ofs = ofs, ofs<<1+1
+		}
+		ofs = min(ofs, maxOfs)
+		lastOfs, ofs = hint-ofs, hint-lastOfs
+	} else {
+		// a[hint+lastOfs] <= key < a[hint+ofs]
+		maxOfs := len(a) - hint
+		for ofs < maxOfs && !less(key, a[hint+ofs]) {
+			lastOfs, ofs = ofs, ofs<<1+1
+		}
+		ofs = min(ofs, maxOfs)
+		lastOfs, ofs = hint+lastOfs, hint+ofs
+	}
+	for lastOfs++; lastOfs < ofs; {
+		m := lastOfs + (ofs-lastOfs)>>1
+		if less(key, a[m]) {
+			ofs = m
+		} else {
+			lastOfs = m + 1
+		}
+	}
+	return ofs
+}
+
+// buffer returns tmp with room for n elements
+func (ts *timSorter[T]) buffer(n int) []T {
+	if cap(ts.tmp) < n {
+		ts.tmp = make([]T, n, max(n, min(2*cap(ts.tmp), len(ts.s)/2)))
+	}
+	return ts.tmp[:n]
+}
+
+// mergeLo merges the adjacent runs s[base1:base1+len1] and s[base2:base2+len2]
+// from the front, the first element of run2 is less than the first of run1 and
+// the last element of run1 is greater than all of run2. run1 is the shorter
+// one and is moved to tmp.
+func (ts *timSorter[T]) mergeLo(base1, len1, base2, len2 int) {
+	s, less := ts.s, ts.less
+	tmp := ts.buffer(len1)
+	copy(tmp, s[base1:base1+len1])
+	cursor1, cursor2, dest := 0, base2, base1
+
+	s[dest] = s[cursor2]
+	dest++
+	cursor2++
+	if len2--; len2 == 0 {
+		copy(s[dest:], tmp[cursor1:cursor1+len1])
+		return
+	}
+	if len1 == 1 {
+		copy(s[dest:], s[cursor2:cursor2+len2])
+		s[dest+len2] = tmp[cursor1]
+		return
+	}
+
+	minGallop := ts.minGallop
+outer:
+	for {
+		// one element at a time until a run wins minGallop times in a row
+		count1, count2 := 0, 0
+		for count1 < minGallop && count2 < minGallop {
+			if less(s[cursor2], tmp[cursor1]) {
+				s[dest] = s[cursor2]
+				dest++
+				cursor2++
+				count1, count2 = 0, count2+1
+				if len2--; len2 == 0 {
+					break outer
+				}
+			} else {
+				s[dest] = tmp[cursor1]
+				dest++
+				cursor1++
+				count1, count2 = count1+1, 0
+				if len1--; len1 == 1 {
+					break outer
+				}
+			}
+		}
+		// gallop while the runs keep winning in long streaks
+		for {
+			count1 = gallopRight(s[cursor2], tmp[cursor1:cursor1+len1], 0, less)
+			if count1 != 0 {
+				copy(s[dest:], tmp[cursor1:cursor1+count1])
+				dest += count1
+				cursor1 += count1
+				if len1 -= count1; len1 <= 1 {
+					break outer
+				}
+			}
+			s[dest] = s[cursor2]
+			dest++
+			cursor2++
+			if len2--; len2 == 0 {
+				break outer
+			}
+
+			count2 = gallopLeft(tmp[cursor1], s[cursor2:cursor2+len2], 0, less)
+			if count2 != 0 {
+				copy(s[dest:], s[cursor2:cursor2+count2])
+				dest += count2
+				cursor2 += count2
+				if len2 -= count2; len2 == 0 {
+					break outer
+				}
+			}
+			s[dest] = tmp[cursor1]
+			dest++
+			cursor1++
+			if len1--; len1 == 1 {
+				break outer
+			}
+			minGallop--
+			if count1 < timMinGallop && count2 < timMinGallop {
+				break
+			}
+		}
+		// leaving the gallop costs more the sooner it happens again
+		minGallop = max(minGallop, 0) + 2
+	}
+	ts.minGallop = max(minGallop, 1)
+
+	if len1 == 1 {
+		copy(s[dest:], s[cursor2:cursor2+len2])
+		s[dest+len2] = tmp[cursor1]
+	} else {
+		copy(s[dest:], tmp[cursor1:cursor1+len1])
+	}
+}
+
+// mergeHi is like mergeLo from the back, run2 is the
+// shorter one and is moved to tmp.
+func (ts *timSorter[T]) mergeHi(base1, len1, base2, len2 int) {
+	s, less := ts.s, ts.less
+	tmp := ts.buffer(len2)
+	copy(tmp, s[base2:base2+len2])
+	cursor1, cursor2, dest := base1+len1-1, len2-1, base2+len2-1
+
+	s[dest] = s[cursor1]
+	dest--
+	cursor1--
+	if len1--; len1 == 0 {
+		copy(s[dest-len2+1:], tmp[:len2])
+		return
+	}
+	if len2 == 1 {
+		dest -= len1
+		cursor1 -= len1
+		copy(s[dest+1:], s[cursor1+1:cursor1+1+len1])
+		s[dest] = tmp[cursor2]
+		return
+	}
+
+	minGallop := ts.minGallop
+outer:
+	for {
+		count1, count2 := 0, 0
+		for count1 < minGallop && count2 < minGallop {
+			if less(tmp[cursor2], s[cursor1]) {
+				s[dest] = s[cursor1]
+				dest--
+				cursor1--
+				count1, count2 = count1+1, 0
+				if len1--; len1 == 0 {
+					break outer
+				}
+			} else {
+				s[dest] = tmp[cursor2]
+				dest--
+				cursor2--
+				count1, count2 = 0, count2+1
+				if len2--; len2 == 1 {
+					break outer
+				}
+			}
+		}
+		for {
+			count1 = len1 - gallopRight(tmp[cursor2], s[base1:base1+len1], len1-1, less)
+			if count1 != 0 {
+				dest -= count1
+				cursor1 -= count1
+				len1 -= count1
+				copy(s[dest+1:], s[cursor1+1:cursor1+1+count1])
+				if len1 == 0 {
+					break outer
+				}
+			}
+			s[dest] = tmp[cursor2]
+			dest--
+			cursor2--
+			if len2--; len2 == 1 {
+				break outer
+			}
+
+			count2 = len2 - gallopLeft(s[cursor1], tmp[:len2], len2-1, less)
+			if count2 != 0 {
+				dest -= count2
+				cursor2 -= count2
+				len2 -= count2
+				copy(s[dest+1:], tmp[cursor2+1:cursor2+1+count2])
+				if len2 <= 1 {
+					break outer
+				}
+			}
+			s[dest] = s[cursor1]
+			dest--
+			cursor1--
+			if len1--; len1 == 0 {
+				break outer
+			}
+			minGallop--
+			if count1 < timMinGallop && count2 < timMinGallop {
+				break
+			}
+		}
+		minGallop = max(minGallop, 0) + 2
+	}
+	ts.minGallop = max(minGallop, 1)
+
+	if len2 == 1 {
+		dest -= len1
+		cursor1 -= len1
+		copy(s[dest+1:], s[cursor1+1:cursor1+1+len1])
+		s[dest] = tmp[cursor2]
+	} else {
+		copy(s[dest-len2+1:], tmp[:len2])
+	}
+}
+
+/*
+Complexity of tim sort：
+	* Best: 	O(n)
+	* Average: 	O(nlog(n))
+	* Worst: 	O(nlog(n))
+	* Memory: 	O(n)
+	* Stable: 	Yes
+	* Wiki: 	https://en.wikipedia.org/wiki/Timsort
+Shortcome from wiki:
+	Timsort is a hybrid, stable sorting algorithm, derived from
+	merge sort and insertion sort, designed to perform well on many
+	kinds of real-world data. The algorithm finds subsequences of
+	the data that are already ordered (runs) and uses them to sort
+	the remainder more efficiently. This is done by merging runs
+	until certain criteria are fulfilled.
+*/
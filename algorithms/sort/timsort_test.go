@@ -0,0 +1,92 @@
+package sort_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+)
+
+// timInputs are inputs with the runs TimSort looks for
+var timInputs = map[string]func(n int) []pair{
+	"random": func(n int) []pair {
+		s := make([]pair, n)
+		for i := range s {
+			s[i].key = rand.Intn(n/8 + 1)
+		}
+		return s
+	},
+	"sorted runs": func(n int) []pair {
+		s := make([]pair, n)
+		for i := range s {
+			s[i].key = i % 1000
+		}
+		return s
+	},
+	"descending runs": func(n int) []pair {
+		s := make([]pair, n)
+		for i := range s {
+			s[i].key = -(i % 777) / 3
+		}
+		return s
+	},
+	// two sorted halves whose blocks interleave, merges gallop over each block
+	"interleaved blocks": func(n int) []pair {
+		s := make([]pair, n)
+		for i := range s {
+			j := i % (n/2 + 1)
+			s[i].key = j/100*200 + i/(n/2+1)*100 + j%100
+		}
+		return s
+	},
+	"sorted with noise": func(n int) []pair {
+		s := make([]pair, n)
+		for i := range s {
+			s[i].key = i
+			if rand.Intn(100) == 0 {
+				s[i].key = rand.Intn(n)
+			}
+		}
+		return s
+	},
+}
+
+func TestTimSortStable(t *testing.T) {
+	for name, gen := range timInputs {
+		for _, n := range []int{0, 1, 31, 32, 33, 100, 1000, 5000, 50000} {
+			s := gen(n)
+			for i := range s {
+				s[i].pos = i
+			}
+			want := slices.Clone(s)
+			slices.SortStableFunc(want, comparePairs)
+
+			isort.TimSortSlice(s, comparePairs)
+			if !slices.Equal(s, want) {
+				t.Fatalf("%s of %d: not stable", name, n)
+			}
+		}
+	}
+}
+
+func TestTimSortPresorted(t *testing.T) {
+	n := 10000
+	for _, name := range []string{"sorted", "reversed", "equal"} {
+		d := &countingInts{t: t, data: killers[name](n), max: n}
+		isort.TimSort(d)
+		if !slices.IsSorted(d.data) {
+			t.Errorf("%s of %d: not sorted", name, n)
+		}
+	}
+}
+
+func TestTimSortInts(t *testing.T) {
+	s := []int(isort.RandomArray(10000, 100))
+	want := slices.Clone(s)
+	slices.Sort(want)
+	isort.TimSortOrdered(s)
+	if !slices.Equal(s, want) {
+		t.Errorf("wanted %v but get %v", want, s)
+	}
+}
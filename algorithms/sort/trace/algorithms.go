@@ -0,0 +1,75 @@
+package trace
+
+import (
+	"math/rand"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+)
+
+// Algorithm is a sort of algorithms/sort which works on a Sortable
+type Algorithm struct {
+	Name string
+	Sort func(data isort.Sortable)
+	// Valid reports whether Sort takes an input of length n, nil takes all
+	Valid func(n int) bool
+}
+
+// Algorithms are the sorts to compare, the quadratic ones first
+var Algorithms = []Algorithm{
+	{Name: "BubbleSort", Sort: isort.BubbleSort},
+	{Name: "InsertionSort", Sort: isort.InsertionSort},
+	{Name: "SelectSort", Sort: isort.SelectSort},
+	{Name: "ShellSort", Sort: isort.ShellSort},
+	{Name: "HeapSort", Sort: isort.HeapSort},
+	{Name: "QuickSort", Sort: isort.QuickSort},
+	{Name: "PdqSort", Sort: isort.PdqSort},
+	{Name: "MergeSort", Sort: isort.MergeSort},
+	{Name: "TimSort", Sort: isort.TimSort},
+	{Name: "InPlaceMergeSort", Sort: isort.InPlaceMergeSort},
+	{Name: "ParallelMergeSort", Sort: func(data isort.Sortable) { isort.ParallelMergeSort(data, isort.ParallelOptions{}) }},
+	{Name: "ParallelQuickSort", Sort: func(data isort.Sortable) { isort.ParallelQuickSort(data, isort.ParallelOptions{}) }},
+	{Name: "SortSmall", Sort: isort.SortSmall, Valid: func(n int) bool { return n <= isort.MaxNetwork }},
+	{Name: "BitonicSort", Sort: func(data isort.Sortable) { isort.BitonicSort(data, isort.ParallelOptions{}) },
+		Valid: func(n int) bool { return n&(n-1) == 0 }},
+}
+
+// Distribution is a kind of input to sort
+type Distribution struct {
+	Name string
+	Gen  func(r *rand.Rand, n int) []int
+}
+
+// Distributions are the inputs to compare the sorts on
+var Distributions = []Distribution{
+	{"random", func(r *rand.Rand, n int) []int {
+		return r.Perm(n)
+	}},
+	{"sorted", func(r *rand.Rand, n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = i
+		}
+		return s
+	}},
+	{"reversed", func(r *rand.Rand, n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = n - i
+		}
+		return s
+	}},
+	{"few-unique", func(r *rand.Rand, n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = r.Intn(8)
+		}
+		return s
+	}},
+	{"organ-pipe", func(r *rand.Rand, n int) []int {
+		s := make([]int, n)
+		for i := range s {
+			s[i] = min(i, n-1-i)
+		}
+		return s
+	}},
+}
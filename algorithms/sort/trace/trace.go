@@ -0,0 +1,183 @@
+/*
+Package trace instruments a sort.Sortable for teaching and choosing sorting
+algorithms: it counts the comparisons and swaps a sort makes and may log them
+as events which can be replayed on a copy of the input, step by step.
+
+	d := trace.NewRecorder(sort.IntSlice(s))
+	sort.HeapSort(d)
+	fmt.Println(d.Counts(), len(d.Log()))
+
+A Sortable cannot write an element to an index, a sort moves elements only by
+Swap, so the events are compares and swaps and there is no write event: a swap
+writes two elements, which Counts.Moves counts. The sorts which merge through a
+buffer, MergeSort, TimSort and ParallelMergeSort, merge a slice of indexes with
+the compares of the data and then put each element in its place with the swaps
+of the permutation. Their compares are those of the algorithm, but their swaps,
+at most n-1, are those of the permutation and not the writes of the merges.
+*/
+package trace
+
+import (
+	"fmt"
+	"sync"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+)
+
+// Op is the kind of a call to the traced data
+type Op uint8
+
+const (
+	// Compare is a call to Less or Equal
+	Compare Op = iota
+	// Swap is a call to Swap
+	Swap
+)
+
+func (op Op) String() string {
+	switch op {
+	case Compare:
+		return "compare"
+	case Swap:
+		return "swap"
+	}
+	return fmt.Sprintf("Op(%d)", uint8(op))
+}
+
+// Event is a call to the traced data with the indexes i and j
+type Event struct {
+	Op   Op
+	I, J int
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf("%v %d,%d", e.Op, e.I, e.J)
+}
+
+// Counts are the calls a sort made to the traced data
+type Counts struct {
+	Compares int64
+	Swaps    int64
+}
+
+// Moves is the number of elements written, a Sortable
+// moves its elements only by Swap which writes two.
+func (c Counts) Moves() int64 {
+	return 2 * c.Swaps
+}
+
+func (c Counts) String() string {
+	return fmt.Sprintf("%d compares, %d swaps, %d moves", c.Compares, c.Swaps, c.Moves())
+}
+
+// Data wraps a sort.Sortable and counts the calls to it, the
+// calls are serialized so that the parallel sorts may trace it.
+type Data struct {
+	data   isort.Sortable
+	record bool
+
+	mu     sync.Mutex
+	counts Counts
+	log    Log
+}
+
+var _ isort.Sortable = (*Data)(nil)
+
+// New returns a Data which counts the calls to data
+func New(data isort.Sortable) *Data {
+	return &Data{data: data}
+}
+
+// NewRecorder returns a Data which counts the calls to data and logs them
+func NewRecorder(data isort.Sortable) *Data {
+	return &Data{data: data, record: true}
+}
+
+// Counts returns the calls counted so far
+func (d *Data) Counts() Counts {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.counts
+}
+
+// Log returns the calls logged so far, nil unless d is a recorder
+func (d *Data) Log() Log {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.log
+}
+
+// Reset clears the counts and the log
+func (d *Data) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.counts = Counts{}
+	d.log = nil
+}
+
+func (d *Data) Len() int { return d.data.Len() }
+
+func (d *Data) Less(i, j int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.add(Compare, i, j)
+	return d.data.Less(i, j)
+}
+
+func (d *Data) Equal(i, j int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.add(Compare, i, j)
+	return d.data.Equal(i, j)
+}
+
+func (d *Data) Swap(i, j int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.add(Swap, i, j)
+	d.data.Swap(i, j)
+}
+
+// add counts a call and logs it, d.mu must be held
+func (d *Data) add(op Op, i, j int) {
+	switch op {
+	case Compare:
+		d.counts.Compares++
+	case Swap:
+		d.counts.Swaps++
+	}
+	if d.record {
+		d.log = append(d.log, Event{Op: op, I: i, J: j})
+	}
+}
+
+// Log is the calls a sort made in order
+type Log []Event
+
+// Counts counts the events of l
+func (l Log) Counts() Counts {
+	var c Counts
+	for _, e := range l {
+		switch e.Op {
+		case Compare:
+			c.Compares++
+		case Swap:
+			c.Swaps++
+		}
+	}
+	return c
+}
+
+// Replay applies the swaps of l to data, which should hold a copy of the
+// input of the sort which made l. step is called with each event after it
+// is applied and stops the replay if it returns false, it may be nil.
+func (l Log) Replay(data isort.Sortable, step func(n int, e Event) bool) {
+	for n, e := range l {
+		if e.Op == Swap {
+			data.Swap(e.I, e.J)
+		}
+		if step != nil && !step(n, e) {
+			return
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package trace_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+	"github.com/man-fish/goalgorithms/algorithms/sort/trace"
+)
+
+func TestCounts(t *testing.T) {
+	d := trace.New(isort.IntSlice{3, 1, 2})
+	isort.InsertionSort(d)
+	// 1 moves before 3, 2 before 3 but not before 1
+	want := trace.Counts{Compares: 3, Swaps: 2}
+	if c := d.Counts(); c != want {
+		t.Errorf("wanted %v but get %v", want, c)
+	}
+	if d.Log() != nil {
+		t.Errorf("wanted no log but get %v", d.Log())
+	}
+}
+
+func TestReplay(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, alg := range trace.Algorithms {
+		n := 200
+		for alg.Valid != nil && !alg.Valid(n) {
+			n--
+		}
+		for _, dist := range trace.Distributions {
+			src := dist.Gen(r, n)
+			s := isort.IntSlice(slices.Clone(src))
+			d := trace.NewRecorder(s)
+			alg.Sort(d)
+			if !slices.IsSorted(s) {
+				t.Fatalf("%s of %s: not sorted", alg.Name, dist.Name)
+			}
+			log := d.Log()
+			if c := log.Counts(); c != d.Counts() {
+				t.Errorf("%s of %s: counted %v but logged %v", alg.Name, dist.Name, d.Counts(), c)
+			}
+			replay := isort.IntSlice(slices.Clone(src))
+			steps := 0
+			log.Replay(replay, func(int, trace.Event) bool { steps++; return true })
+			if !slices.Equal(replay, s) || steps != len(log) {
+				t.Errorf("%s of %s: replay of %d steps get %v", alg.Name, dist.Name, steps, replay)
+			}
+		}
+	}
+}
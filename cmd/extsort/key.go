@@ -0,0 +1,232 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// keyType is how the values of a key column compare
+type keyType int
+
+const (
+	lexical keyType = iota
+	numeric
+	date
+)
+
+// keySpec is a key column given with -k
+type keySpec struct {
+	// col is the 1-based column of the key
+	col  int
+	typ  keyType
+	desc bool
+}
+
+// parseKey parses a key spec like GNU sort -k, the column number
+// followed by the letters n (numeric), t (date) and r (descending).
+// A GNU range like 2,2n is accepted when both ends are the same column.
+func parseKey(s string) (keySpec, error) {
+	spec := s
+	if i := strings.IndexByte(s, ','); i >= 0 {
+		end := strings.TrimRight(s[i+1:], "ntr")
+		if end != strings.TrimRight(s[:i], "ntr") {
+			return keySpec{}, fmt.Errorf("key %q: only single column keys are supported", spec)
+		}
+		s = s[:i] + s[i+1+len(end):]
+	}
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	col, err := strconv.Atoi(s[:i])
+	if err != nil || col < 1 {
+		return keySpec{}, fmt.Errorf("key %q: bad column", spec)
+	}
+	k := keySpec{col: col}
+	for _, c := range s[i:] {
+		switch c {
+		case 'n':
+			k.typ = numeric
+		case 't':
+			k.typ = date
+		case 'r':
+			k.desc = true
+		default:
+			return keySpec{}, fmt.Errorf("key %q: unknown option %q", spec, c)
+		}
+	}
+	return k, nil
+}
+
+// keyList collects the -k flags
+type keyList []keySpec
+
+func (l *keyList) String() string { return fmt.Sprint(*l) }
+
+func (l *keyList) Set(s string) error {
+	k, err := parseKey(s)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, k)
+	return nil
+}
+
+// dateLayouts are tried in order when no date layout is given
+var dateLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02", time.RFC1123Z, time.RFC1123}
+
+// keyValue is a key column parsed by the type of its key,
+// values which do not parse sort before all others.
+type keyValue struct {
+	s  string
+	n  float64
+	ok bool
+	// d is the exact value of a numeric key if num is set
+	d   decimal
+	num bool
+}
+
+// decimal is the exact value of a number, it is 0.digits times 10^point.
+// Every numeric key compares as a decimal so that numbers compare exactly
+// however many digits they have and in one order whatever their notation.
+type decimal struct {
+	neg bool
+	// inf is set for the infinities, whose digits are empty
+	inf bool
+	// digits has no leading or trailing zeros, it is empty for zero
+	digits string
+	point  int64
+}
+
+// maxExponent bounds the exponents of numeric keys so that the point
+// of a decimal cannot overflow
+const maxExponent = 1 << 40
+
+// parseDecimal parses an optional sign, digits, an optional fraction and an
+// optional exponent. The other numbers of strconv.ParseFloat, the infinities
+// and hex numbers, are converted from their float64, NaN does not parse.
+func parseDecimal(s string) (decimal, bool) {
+	var d decimal
+	num := s
+	if num != "" && (num[0] == '+' || num[0] == '-') {
+		d.neg, num = num[0] == '-', num[1:]
+	}
+	mantissa, exponent := num, "0"
+	if i := strings.IndexAny(num, "eE"); i >= 0 {
+		mantissa, exponent = num[:i], num[i+1:]
+	}
+	whole, frac, _ := strings.Cut(mantissa, ".")
+	exp, err := strconv.ParseInt(exponent, 10, 64)
+	if whole == "" && frac == "" || !digits(whole) || !digits(frac) || err != nil {
+		return parseFloat(s)
+	}
+	if exp < -maxExponent || exp > maxExponent {
+		return decimal{}, false
+	}
+	all := whole + frac
+	lead := len(all) - len(strings.TrimLeft(all, "0"))
+	d.digits = strings.TrimRight(all[lead:], "0")
+	d.point = int64(len(whole)-lead) + exp
+	if d.digits == "" {
+		d.neg, d.point = false, 0
+	}
+	return d, true
+}
+
+// parseFloat parses s with strconv.ParseFloat as the exact decimal of its float64
+func parseFloat(s string) (decimal, bool) {
+	n, err := strconv.ParseFloat(s, 64)
+	switch {
+	case err != nil && !errors.Is(err, strconv.ErrRange), math.IsNaN(n):
+		return decimal{}, false
+	case math.IsInf(n, 0):
+		return decimal{neg: n < 0, inf: true}, true
+	}
+	// 767 digits write every float64 exactly
+	d, _ := parseDecimal(strconv.FormatFloat(n, 'e', 767, 64))
+	return d, true
+}
+
+func digits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareDecimals returns -1, 0 or 1 as a is less than, equal to or greater than b
+func compareDecimals(a, b decimal) int {
+	if a.neg != b.neg {
+		if a.neg {
+			return -1
+		}
+		return 1
+	}
+	var c int
+	switch {
+	case a.inf || b.inf:
+		c = cmp.Compare(btoi(a.inf), btoi(b.inf))
+	case a.digits == "" || b.digits == "":
+		c = cmp.Compare(len(a.digits), len(b.digits))
+	default:
+		c = cmp.Compare(a.point, b.point)
+		if c == 0 {
+			c = strings.Compare(a.digits, b.digits)
+		}
+	}
+	if a.neg {
+		return -c
+	}
+	return c
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// parseValue parses field as a value of key type typ
+func parseValue(field string, typ keyType, layouts []string) keyValue {
+	switch typ {
+	case numeric:
+		d, ok := parseDecimal(strings.TrimSpace(field))
+		return keyValue{d: d, num: true, ok: ok}
+	case date:
+		field = strings.TrimSpace(field)
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, field); err == nil {
+				// seconds and nanoseconds keep the full range and precision of time
+				return keyValue{n: float64(t.Unix()), s: fmt.Sprintf("%09d", t.Nanosecond()), ok: true}
+			}
+		}
+		return keyValue{}
+	}
+	return keyValue{s: field, ok: true}
+}
+
+// compareValues returns -1, 0 or 1 as a is before, equal to or after b
+func compareValues(a, b keyValue) int {
+	switch {
+	case a.ok != b.ok:
+		if a.ok {
+			return 1
+		}
+		return -1
+	case a.num && b.num:
+		return compareDecimals(a.d, b.d)
+	case a.n < b.n:
+		return -1
+	case a.n > b.n:
+		return 1
+	}
+	return strings.Compare(a.s, b.s)
+}
@@ -0,0 +1,264 @@
+/*
+Command extsort sorts CSV, TSV and line files that may be much larger than
+memory by one or more key columns, in the manner of GNU sort -k:
+
+	extsort -k 3n -k 1r --memory 256M --tmpdir /var/tmp -o out.csv in.csv
+
+Each -k names a 1-based column followed by the letters n (numeric), t (date)
+and r (descending), a key without a type compares its values as strings.
+Records with equal keys keep their order in the input, values which do not
+parse as their type sort before all others. Without -k whole records are
+compared. The runs are made with replacement selection, or with --parallel
+above 1 by sorting chunks of the input in memory with pdqsort on several
+workers, and merged with a loser tree by algorithms/sort/externalsort.
+*/
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/man-fish/goalgorithms/algorithms/sort/externalsort"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if err != errUsage {
+			fmt.Fprintln(os.Stderr, "extsort:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// errUsage reports bad args, which the flag set already printed
+var errUsage = errors.New("usage")
+
+// run parses the command line args and sorts the input files,
+// or stdin if there are none, to the output file or stdout.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var (
+		keys keyList
+		fs   = flag.NewFlagSet("extsort", flag.ContinueOnError)
+	)
+	fs.SetOutput(stderr)
+	fs.Var(&keys, "k", "sort by key `COL[ntr]`, may be repeated")
+	formatName := fs.String("format", "", "input `format`: csv, tsv or lines, from the file extension if empty")
+	sep := fs.String("t", "", "column `separator` of lines, runs of white space if empty")
+	header := fs.Bool("header", false, "keep the first record of the input first")
+	layout := fs.String("date-layout", "", "time.Parse `layout` of date keys, common layouts are tried if empty")
+	memory := fs.String("memory", "64M", "memory `budget` like 512K, 64M or 2G")
+	tmpdir := fs.String("tmpdir", "", "`dir` of the temporary runs, os.TempDir() if empty")
+	parallel := fs.Int("parallel", 1, "number of `workers` sorting and merging at the same time")
+	output := fs.String("o", "", "output `file`, stdout if empty")
+	if err := fs.Parse(splitKeys(args)); err == flag.ErrHelp {
+		return nil
+	} else if err != nil {
+		return errUsage
+	}
+
+	budget, err := parseSize(*memory)
+	if err != nil {
+		return err
+	}
+	f, err := formatOf(*formatName, *sep, fs.Args())
+	if err != nil {
+		return err
+	}
+	codec := &recordCodec{format: f, keys: keys, layouts: dateLayouts}
+	if *layout != "" {
+		codec.layouts = []string{*layout}
+	}
+
+	inputs := []io.Reader{stdin}
+	if fs.NArg() > 0 {
+		inputs = inputs[:0]
+		for _, name := range fs.Args() {
+			if name == "-" {
+				inputs = append(inputs, stdin)
+				continue
+			}
+			file, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			inputs = append(inputs, file)
+		}
+	}
+	opts := externalsort.Options{TempDir: *tmpdir, MemoryBudget: budget, Workers: *parallel}
+	if *output == "" {
+		return sortRecords(ctx, inputs, stdout, codec, *header, opts)
+	}
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	err = sortRecords(ctx, inputs, file, codec, *header, opts)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// splitKeys splits the GNU style keys like -k2nr into two args
+func splitKeys(args []string) []string {
+	var split []string
+	for i, arg := range args {
+		if arg == "--" {
+			return append(split, args[i:]...)
+		}
+		if len(arg) > 2 && arg[:2] == "-k" && arg[2] >= '0' && arg[2] <= '9' {
+			split = append(split, "-k", arg[2:])
+			continue
+		}
+		split = append(split, arg)
+	}
+	return split
+}
+
+// sortRecords sorts the records of inputs to out, the records are numbered
+// and encoded for the external sort on their way in and decoded on their way out.
+func sortRecords(ctx context.Context, inputs []io.Reader, out io.Writer, codec *recordCodec, header bool, opts externalsort.Options) error {
+	write, flush := codec.format.writer(out)
+	readers := make([]func() ([]string, error), len(inputs))
+	for i, in := range inputs {
+		readers[i] = codec.format.reader(in)
+		if !header {
+			continue
+		}
+		// every file has a header, the first one is kept
+		fields, err := readers[i]()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			if err := write(fields); err != nil {
+				return err
+			}
+		}
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(encodeRecords(readers, pw, codec))
+	}()
+	or, ow := io.Pipe()
+	decoded := make(chan error, 1)
+	go func() {
+		err := decodeRecords(or, write, codec)
+		or.CloseWithError(err)
+		decoded <- err
+	}()
+
+	err := externalsort.SortFunc(ctx, pr, ow, codec, codec.less, opts)
+	pr.CloseWithError(errors.New("extsort: sort stopped"))
+	ow.CloseWithError(err)
+	if derr := <-decoded; err == nil {
+		err = derr
+	}
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// encodeRecords numbers the records read by readers and encodes them to w
+func encodeRecords(readers []func() ([]string, error), w io.Writer, codec *recordCodec) error {
+	bw := bufio.NewWriter(w)
+	var seq uint64
+	for _, read := range readers {
+		for {
+			fields, err := read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if err := codec.Encode(bw, record{seq: seq, fields: fields}); err != nil {
+				return err
+			}
+			seq++
+		}
+	}
+	return bw.Flush()
+}
+
+// decodeRecords decodes the sorted records of r and writes them with write
+func decodeRecords(r io.Reader, write func([]string) error, codec *recordCodec) error {
+	br := bufio.NewReader(r)
+	for {
+		rec, err := codec.Decode(br)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := write(rec.fields); err != nil {
+			return err
+		}
+	}
+}
+
+// formatOf returns the format named name, or the format of the extension
+// of the first file if name is empty, files of other extensions are lines.
+func formatOf(name, sep string, files []string) (format, error) {
+	byExt := name == ""
+	if byExt && len(files) > 0 {
+		name = strings.TrimPrefix(filepath.Ext(files[0]), ".")
+	}
+	switch strings.ToLower(name) {
+	case "csv":
+		return csvFormat{}, nil
+	case "tsv":
+		return lineFormat{sep: "\t"}, nil
+	case "lines":
+		return lineFormat{sep: sep}, nil
+	}
+	if byExt {
+		return lineFormat{sep: sep}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q", name)
+}
+
+// parseSize parses a byte count with an optional K, M or G suffix
+func parseSize(s string) (int64, error) {
+	digits, shift := strings.TrimSuffix(strings.ToUpper(s), "B"), 0
+	if i := len(digits) - 1; i >= 0 {
+		switch digits[i] {
+		case 'K':
+			shift = 10
+		case 'M':
+			shift = 20
+		case 'G':
+			shift = 30
+		}
+		if shift > 0 {
+			digits = digits[:i]
+		}
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("bad memory budget %q", s)
+	}
+	if n > math.MaxInt64>>shift {
+		return 0, fmt.Errorf("memory budget %q too large", s)
+	}
+	return n << shift, nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseKey(t *testing.T) {
+	cases := []struct {
+		spec string
+		want keySpec
+	}{
+		{"1", keySpec{col: 1}},
+		{"3n", keySpec{col: 3, typ: numeric}},
+		{"2tr", keySpec{col: 2, typ: date, desc: true}},
+		{"12,12nr", keySpec{col: 12, typ: numeric, desc: true}},
+	}
+	for _, c := range cases {
+		k, err := parseKey(c.spec)
+		if err != nil || k != c.want {
+			t.Errorf("parse %q: wanted %+v but get %+v, %v", c.spec, c.want, k, err)
+		}
+	}
+	for _, spec := range []string{"", "0", "n", "2x", "2,3"} {
+		if _, err := parseKey(spec); err == nil {
+			t.Errorf("parse %q: wanted an error", spec)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	cases := map[string]int64{"4096": 4096, "512K": 512 << 10, "64m": 64 << 20, "2GB": 2 << 30}
+	for s, want := range cases {
+		if n, err := parseSize(s); err != nil || n != want {
+			t.Errorf("parse %q: wanted %d but get %d, %v", s, want, n, err)
+		}
+	}
+	for _, s := range []string{"", "M", "-1K", "12T", "9999999999G", "9223372036854775807K"} {
+		if _, err := parseSize(s); err == nil {
+			t.Errorf("parse %q: wanted an error", s)
+		}
+	}
+}
+
+func TestCompareNumbers(t *testing.T) {
+	// ordered, the 64-bit ids differ beyond the precision of a float64
+	numbers := []string{"x", "-Inf", "-1e3", "-12.5", "-12.25", "-0.5", "-0", "0.000", "0e99", "5e-324", "0.05", "0.5", "1", "001.5", "2e0",
+		"9007199254740992", "9.007199254740993e15", "9007199254740994", "18446744073709551615", "18446744073709551616",
+		"0x1p70", "1e30", "1e400", "+Inf"}
+	for i := 1; i < len(numbers); i++ {
+		a := parseValue(numbers[i-1], numeric, nil)
+		b := parseValue(numbers[i], numeric, nil)
+		if numbers[i] == "0.000" || numbers[i] == "0e99" {
+			// -0, 0.000 and 0e99 are equal
+			if compareValues(a, b) != 0 || compareValues(b, a) != 0 {
+				t.Errorf("wanted %s equal to %s", numbers[i-1], numbers[i])
+			}
+		} else if compareValues(a, b) >= 0 || compareValues(b, a) <= 0 {
+			t.Errorf("wanted %s before %s", numbers[i-1], numbers[i])
+		}
+	}
+	// one order whatever the notation, which a float64 would break
+	a, b, c := parseValue("9007199254740993", numeric, nil), parseValue("9007199254740992", numeric, nil), parseValue("9.007199254740992e15", numeric, nil)
+	if compareValues(a, b) <= 0 || compareValues(b, c) != 0 || compareValues(a, c) <= 0 {
+		t.Errorf("wanted 9007199254740993 after 9007199254740992 and its exponent form")
+	}
+}
+
+func TestCompareDates(t *testing.T) {
+	dates := []string{"bad", "1969-12-31", "2020-05-01", "2020-05-01T00:00:00.5Z", "2020-05-01 00:00:01"}
+	for i := 1; i < len(dates); i++ {
+		a := parseValue(dates[i-1], date, dateLayouts)
+		b := parseValue(dates[i], date, dateLayouts)
+		if compareValues(a, b) >= 0 || compareValues(b, a) <= 0 {
+			t.Errorf("wanted %s before %s", dates[i-1], dates[i])
+		}
+	}
+}
+
+// row is a record of the test input
+type row struct {
+	name string
+	n    int
+	day  time.Time
+	seq  int
+}
+
+func writeRows(t *testing.T, rows []row) string {
+	path := filepath.Join(t.TempDir(), "in.csv")
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	w.Write([]string{"name", "n", "day"})
+	for _, r := range rows {
+		w.Write([]string{r.name, strconv.Itoa(r.n), r.day.Format("2006-01-02")})
+	}
+	w.Flush()
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunCSV(t *testing.T) {
+	rows := make([]row, 20000)
+	for i := range rows {
+		rows[i] = row{
+			name: fmt.Sprintf("name, %d", rand.Intn(50)),
+			n:    rand.Intn(100) - 50,
+			day:  time.Date(2000, 1, 1+rand.Intn(30), 0, 0, 0, 0, time.UTC),
+			seq:  i,
+		}
+	}
+	in := writeRows(t, rows)
+
+	// ascending days, descending numbers, names keep the input order
+	sort.SliceStable(rows, func(i, j int) bool {
+		if !rows[i].day.Equal(rows[j].day) {
+			return rows[i].day.Before(rows[j].day)
+		}
+		return rows[i].n > rows[j].n
+	})
+	for _, parallel := range []string{"1", "4"} {
+		var out bytes.Buffer
+		args := []string{"-k3t", "-k", "2nr", "--header", "--memory", "64K", "--parallel", parallel, "--tmpdir", t.TempDir(), in}
+		if err := run(context.Background(), args, nil, &out, os.Stderr); err != nil {
+			t.Fatal(err)
+		}
+		records, err := csv.NewReader(&out).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != len(rows)+1 || !reflect.DeepEqual(records[0], []string{"name", "n", "day"}) {
+			t.Fatalf("wanted the header and %d records but get %d records", len(rows), len(records))
+		}
+		for i, r := range rows {
+			want := []string{r.name, strconv.Itoa(r.n), r.day.Format("2006-01-02")}
+			if !reflect.DeepEqual(records[i+1], want) {
+				t.Fatalf("parallel %s: at %d wanted %v but get %v", parallel, i, want, records[i+1])
+			}
+		}
+	}
+}
+
+func TestRunLines(t *testing.T) {
+	in := "b 2\na 10\n c\td 1\na 2\n"
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{nil, " c\td 1\na 10\na 2\nb 2\n"},
+		// d is not a number so it sorts first
+		{[]string{"-k2n"}, " c\td 1\nb 2\na 2\na 10\n"},
+		{[]string{"-k", "1r"}, " c\td 1\nb 2\na 10\na 2\n"},
+		{[]string{"-t", " ", "-k2"}, "a 10\nb 2\na 2\n c\td 1\n"},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		if err := run(context.Background(), c.args, strings.NewReader(in), &out, os.Stderr); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != c.want {
+			t.Errorf("args %q: wanted %q but get %q", c.args, c.want, out.String())
+		}
+	}
+}
+
+func TestRunCRLF(t *testing.T) {
+	// each line keeps its terminator, the \r is not part of the last column
+	in := "b 2\r\na 10\r\nc 1\n"
+	for args, want := range map[string]string{"": "a 10\r\nb 2\r\nc 1\n", "-k2n": "c 1\nb 2\r\na 10\r\n"} {
+		var out bytes.Buffer
+		if err := run(context.Background(), strings.Fields(args), strings.NewReader(in), &out, os.Stderr); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != want {
+			t.Errorf("args %q: wanted %q but get %q", args, want, out.String())
+		}
+	}
+}
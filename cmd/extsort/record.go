@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"unsafe"
+)
+
+// format reads and writes the records of the input and output files
+type format interface {
+	// reader returns a func reading the fields of the next record of r
+	reader(r io.Reader) func() ([]string, error)
+	// writer returns a func writing the fields of a record to w and a
+	// func flushing w
+	writer(w io.Writer) (func(fields []string) error, func() error)
+	// column returns the 1-based column col of a record, "" if it is missing
+	column(fields []string, col int) string
+}
+
+// csvFormat is comma separated values as read by encoding/csv
+type csvFormat struct{}
+
+func (csvFormat) reader(r io.Reader) func() ([]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	return cr.Read
+}
+
+func (csvFormat) writer(w io.Writer) (func([]string) error, func() error) {
+	cw := csv.NewWriter(w)
+	return cw.Write, func() error {
+		cw.Flush()
+		return cw.Error()
+	}
+}
+
+func (csvFormat) column(fields []string, col int) string {
+	if col > len(fields) {
+		return ""
+	}
+	return fields[col-1]
+}
+
+// lineFormat is a record per line, the columns are separated by sep
+// or by runs of white space if sep is empty. The line is kept as is.
+type lineFormat struct {
+	sep string
+}
+
+func (lineFormat) reader(r io.Reader) func() ([]string, error) {
+	br := bufio.NewReader(r)
+	return func() ([]string, error) {
+		line, err := br.ReadString('\n')
+		if err == io.EOF && len(line) > 0 {
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSuffix(line, "\n")
+		// the \r of a CRLF line is not part of its columns but written back
+		if line, ok := strings.CutSuffix(line, "\r"); ok {
+			return []string{line, "\r"}, nil
+		}
+		return []string{line}, nil
+	}
+}
+
+func (lineFormat) writer(w io.Writer) (func([]string) error, func() error) {
+	bw := bufio.NewWriter(w)
+	return func(fields []string) error {
+		for _, f := range fields {
+			bw.WriteString(f)
+		}
+		return bw.WriteByte('\n')
+	}, bw.Flush
+}
+
+func (f lineFormat) column(fields []string, col int) string {
+	var cols []string
+	if f.sep == "" {
+		cols = strings.Fields(fields[0])
+	} else {
+		cols = strings.SplitN(fields[0], f.sep, col+1)
+	}
+	if col > len(cols) {
+		return ""
+	}
+	return cols[col-1]
+}
+
+// record is a record of the input with its keys, seq is
+// its position in the input which makes the sort stable.
+type record struct {
+	seq    uint64
+	fields []string
+	keys   []keyValue
+}
+
+// recordCodec is the externalsort.Codec of the runs, it parses
+// the keys of the records as they are decoded.
+type recordCodec struct {
+	format  format
+	keys    []keySpec
+	layouts []string
+}
+
+var errShortRecord = errors.New("extsort: run ends in the middle of a record")
+
+// Encode implements externalsort.Codec
+func (c *recordCodec) Encode(w *bufio.Writer, r record) error {
+	var buf [binary.MaxVarintLen64]byte
+	w.Write(binary.AppendUvarint(buf[:0], r.seq))
+	w.Write(binary.AppendUvarint(buf[:0], uint64(len(r.fields))))
+	for _, f := range r.fields {
+		w.Write(binary.AppendUvarint(buf[:0], uint64(len(f))))
+		if _, err := w.WriteString(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Decode implements externalsort.Codec
+func (c *recordCodec) Decode(r *bufio.Reader) (record, error) {
+	seq, err := binary.ReadUvarint(r)
+	if err != nil {
+		return record{}, err
+	}
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return record{}, short(err)
+	}
+	rec := record{seq: seq, fields: make([]string, n)}
+	for i := range rec.fields {
+		size, err := binary.ReadUvarint(r)
+		if err != nil {
+			return record{}, short(err)
+		}
+		b := make([]byte, size)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return record{}, short(err)
+		}
+		rec.fields[i] = string(b)
+	}
+	c.parseKeys(&rec)
+	return rec, nil
+}
+
+func short(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errShortRecord
+	}
+	return err
+}
+
+// Size implements externalsort.Codec
+func (c *recordCodec) Size(r record) int {
+	n := len(r.fields)*int(unsafe.Sizeof("")) + len(r.keys)*int(unsafe.Sizeof(keyValue{}))
+	for _, f := range r.fields {
+		n += len(f)
+	}
+	for _, k := range r.keys {
+		n += len(k.s)
+	}
+	return n
+}
+
+func (c *recordCodec) parseKeys(r *record) {
+	if len(c.keys) == 0 {
+		return
+	}
+	r.keys = make([]keyValue, len(c.keys))
+	for i, k := range c.keys {
+		r.keys[i] = parseValue(c.format.column(r.fields, k.col), k.typ, c.layouts)
+	}
+}
+
+// less orders records by their keys, or by the whole record if there
+// are no keys, and records with equal keys by their position in the input.
+func (c *recordCodec) less(a, b record) bool {
+	if len(c.keys) == 0 {
+		if cmp := compareFields(a.fields, b.fields); cmp != 0 {
+			return cmp < 0
+		}
+	}
+	for i, k := range c.keys {
+		cmp := compareValues(a.keys[i], b.keys[i])
+		if k.desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp < 0
+		}
+	}
+	return a.seq < b.seq
+}
+
+func compareFields(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if cmp := strings.Compare(a[i], b[i]); cmp != 0 {
+			return cmp
+		}
+	}
+	return len(a) - len(b)
+}
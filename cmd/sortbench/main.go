@@ -0,0 +1,217 @@
+/*
+Command sortbench compares the sorting algorithms of algorithms/sort: it runs
+each of them over inputs of several distributions and prints a table of the
+comparisons and swaps they made, counted by algorithms/sort/trace, and of the
+time they took without tracing:
+
+	sortbench -n 1000,100000 -alg PdqSort,TimSort -dist random,sorted
+
+The key based sorts, which take no Sortable, are timed but have no counts.
+Sorts which do not take an input, like BitonicSort of a length which is not
+a power of two or PigeonholeSort of too wide a range, show n/a.
+*/
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+	"github.com/man-fish/goalgorithms/algorithms/sort/trace"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if err != errUsage {
+			fmt.Fprintln(os.Stderr, "sortbench:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// errUsage reports bad args, which the flag set already printed
+var errUsage = errors.New("usage")
+
+// keySorts are the sorts of algorithms/sort which are not comparison sorts,
+// prepare converts an input to the type the sort takes and returns the sort
+// of it, so that the conversion is not timed.
+var keySorts = []struct {
+	name    string
+	prepare func(s []int) func() error
+}{
+	{"CountingSort", func(s []int) func() error {
+		return func() error { isort.CountingSort(s); return nil }
+	}},
+	{"RadixSort", func(s []int) func() error {
+		return func() error { isort.RadixSort(s); return nil }
+	}},
+	{"RadixSortInt64", func(s []int) func() error {
+		keys := convert(s, func(v int) int64 { return int64(v) })
+		return func() error { isort.RadixSortInt64(keys); return nil }
+	}},
+	{"RadixSortUint64", func(s []int) func() error {
+		// the inputs are not negative
+		keys := convert(s, func(v int) uint64 { return uint64(v) })
+		return func() error { isort.RadixSortUint64(keys); return nil }
+	}},
+	{"RadixSortFloat64", func(s []int) func() error {
+		keys := convert(s, func(v int) float64 { return float64(v) })
+		return func() error { isort.RadixSortFloat64(keys); return nil }
+	}},
+	{"RadixSortStrings", func(s []int) func() error {
+		keys := convert(s, func(v int) string { return fmt.Sprintf("%020d", v) })
+		return func() error { isort.RadixSortStrings(keys); return nil }
+	}},
+	{"RadixSortBytes", func(s []int) func() error {
+		keys := convert(s, func(v int) []byte { return fmt.Appendf(nil, "%020d", v) })
+		return func() error { isort.RadixSortBytes(keys); return nil }
+	}},
+	{"RadixSortBy", func(s []int) func() error {
+		return func() error { isort.RadixSortBy(s, func(v int) uint64 { return uint64(v) }); return nil }
+	}},
+	{"PigeonholeSort", func(s []int) func() error {
+		return func() error { return isort.PigeonholeSort(s, 0) }
+	}},
+	{"BucketSort", func(s []int) func() error {
+		keys := convert(s, func(v int) float64 { return float64(v) })
+		return func() error { isort.BucketSort(keys, isort.BucketOptions{}); return nil }
+	}},
+	{"FlashSort", func(s []int) func() error {
+		keys := convert(s, func(v int) float64 { return float64(v) })
+		return func() error { isort.FlashSort(keys); return nil }
+	}},
+}
+
+// convert returns the elements of s converted by f
+func convert[T any](s []int, f func(v int) T) []T {
+	t := make([]T, len(s))
+	for i, v := range s {
+		t[i] = f(v)
+	}
+	return t
+}
+
+// run parses the command line args and prints the table to stdout
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("sortbench", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	sizes := fs.String("n", "1000,10000", "comma separated `sizes` of the inputs")
+	algs := fs.String("alg", "", "comma separated `algorithms` to run, all if empty")
+	dists := fs.String("dist", "", "comma separated `distributions` of the inputs, all if empty")
+	seed := fs.Int64("seed", 1, "`seed` of the random inputs")
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return nil
+	} else if err != nil {
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "sortbench: unexpected args %q\n", fs.Args())
+		return errUsage
+	}
+
+	var ns []int
+	for _, s := range strings.Split(*sizes, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n < 0 {
+			return fmt.Errorf("bad size %q", s)
+		}
+		ns = append(ns, n)
+	}
+	algNames, err := selected(*algs, algorithmNames())
+	if err != nil {
+		return err
+	}
+	var distNames []string
+	for _, d := range trace.Distributions {
+		distNames = append(distNames, d.Name)
+	}
+	if distNames, err = selected(*dists, distNames); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "algorithm\tinput\tn\tcompares\tswaps\tmoves\ttime\t")
+	r := rand.New(rand.NewSource(*seed))
+	for _, n := range ns {
+		for _, dist := range trace.Distributions {
+			if !slices.Contains(distNames, dist.Name) {
+				continue
+			}
+			src := dist.Gen(r, n)
+			for _, alg := range trace.Algorithms {
+				if !slices.Contains(algNames, alg.Name) {
+					continue
+				}
+				if alg.Valid != nil && !alg.Valid(n) {
+					fmt.Fprintf(tw, "%s\t%s\t%d\tn/a\tn/a\tn/a\tn/a\t\n", alg.Name, dist.Name, n)
+					continue
+				}
+				d := trace.New(isort.IntSlice(slices.Clone(src)))
+				alg.Sort(d)
+				c := d.Counts()
+				s := isort.IntSlice(slices.Clone(src))
+				elapsed := timed(func() { alg.Sort(s) })
+				fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%v\t\n", alg.Name, dist.Name, n, c.Compares, c.Swaps, c.Moves(), elapsed)
+			}
+			for _, alg := range keySorts {
+				if !slices.Contains(algNames, alg.name) {
+					continue
+				}
+				sort := alg.prepare(slices.Clone(src))
+				var err error
+				elapsed := timed(func() { err = sort() })
+				if err != nil {
+					// e.g. PigeonholeSort refused a range, the input is not sorted
+					fmt.Fprintf(tw, "%s\t%s\t%d\t-\t-\t-\tn/a\t\n", alg.name, dist.Name, n)
+					continue
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%d\t-\t-\t-\t%v\t\n", alg.name, dist.Name, n, elapsed)
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+// algorithmNames lists the comparison sorts and then the key based ones
+func algorithmNames() []string {
+	var names []string
+	for _, alg := range trace.Algorithms {
+		names = append(names, alg.Name)
+	}
+	for _, alg := range keySorts {
+		names = append(names, alg.name)
+	}
+	return names
+}
+
+// selected returns the names of the comma separated list,
+// which must be in all, or all if the list is empty.
+func selected(list string, all []string) ([]string, error) {
+	if list == "" {
+		return all, nil
+	}
+	names := strings.Split(list, ",")
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+		if !slices.Contains(all, names[i]) {
+			return nil, fmt.Errorf("unknown %q, wanted one of %s", names[i], strings.Join(all, ", "))
+		}
+	}
+	return names, nil
+}
+
+// timed returns the time f takes, rounded for the table
+func timed(f func()) time.Duration {
+	start := time.Now()
+	f()
+	return time.Since(start).Round(time.Microsecond)
+}
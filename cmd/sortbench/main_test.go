@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	var out, errOut bytes.Buffer
+	err := run([]string{"-n", "10,100", "-alg", "HeapSort, RadixSort", "-dist", "sorted"}, &out, &errOut)
+	if err != nil {
+		t.Fatalf("run: %v, %s", err, errOut.String())
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("wanted a header and 4 rows but get:\n%s", out.String())
+	}
+	if row := strings.Fields(lines[1]); len(row) != 7 || row[0] != "HeapSort" || row[1] != "sorted" || row[2] != "10" {
+		t.Errorf("wanted a HeapSort row but get %q", lines[1])
+	}
+	if row := strings.Fields(lines[2]); len(row) != 7 || row[0] != "RadixSort" || row[3] != "-" {
+		t.Errorf("wanted a RadixSort row without counts but get %q", lines[2])
+	}
+}
+
+func TestRunInvalidLength(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if err := run([]string{"-n", "10,16", "-alg", "BitonicSort", "-dist", "random"}, &out, &errOut); err != nil {
+		t.Fatalf("run: %v, %s", err, errOut.String())
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if row := strings.Fields(lines[1]); len(row) != 7 || row[3] != "n/a" || row[6] != "n/a" {
+		t.Errorf("wanted n/a for 10 elements but get %q", lines[1])
+	}
+	if row := strings.Fields(lines[2]); len(row) != 7 || row[3] == "n/a" {
+		t.Errorf("wanted counts for 16 elements but get %q", lines[2])
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	for _, args := range [][]string{{"-n", "x"}, {"-alg", "FooSort"}, {"-dist", "zipf"}, {"-bad"}, {"extra"}} {
+		var out, errOut bytes.Buffer
+		if err := run(args, &out, &errOut); err == nil {
+			t.Errorf("run %q: wanted an error", args)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Command sortviz serves a local web page which animates the sorting algorithms
+of algorithms/sort as bar charts, step by step:
+
+	sortviz -addr localhost:8080
+
+The page shows two algorithms side by side on the same input, with play,
+pause and step controls. The steps are the compares and swaps the sorts make
+on a Sortable traced by algorithms/sort/trace, which the page replays. The
+page is served from the binary and needs no external assets.
+*/
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+
+	isort "github.com/man-fish/goalgorithms/algorithms/sort"
+	"github.com/man-fish/goalgorithms/algorithms/sort/trace"
+)
+
+const (
+	// maxLength is the longest input the page may ask for
+	maxLength = 1024
+	// maxEvents bounds the steps of a trace, a quadratic sort of
+	// maxLength elements makes about a million of them
+	maxEvents = 1 << 21
+)
+
+//go:embed page.html
+var page []byte
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		if err != errUsage {
+			fmt.Fprintln(os.Stderr, "sortviz:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// errUsage reports bad args, which the flag set already printed
+var errUsage = errors.New("usage")
+
+// run parses the command line args and serves the page until it fails
+func run(args []string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("sortviz", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", "localhost:8080", "`address` to serve the page on")
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return nil
+	} else if err != nil {
+		return errUsage
+	}
+	log.Printf("serving on http://%s/", *addr)
+	return http.ListenAndServe(*addr, newHandler())
+}
+
+// newHandler serves the page, the lists of algorithms and
+// distributions on /algorithms and the traces on /trace.
+func newHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	})
+	mux.HandleFunc("GET /algorithms", serveAlgorithms)
+	mux.HandleFunc("GET /trace", serveTrace)
+	return mux
+}
+
+// choices are the names the page may pick from
+type choices struct {
+	Algorithms    []string `json:"algorithms"`
+	Distributions []string `json:"distributions"`
+	MaxLength     int      `json:"maxLength"`
+}
+
+func serveAlgorithms(w http.ResponseWriter, r *http.Request) {
+	c := choices{MaxLength: maxLength}
+	for _, alg := range trace.Algorithms {
+		c.Algorithms = append(c.Algorithms, alg.Name)
+	}
+	for _, dist := range trace.Distributions {
+		c.Distributions = append(c.Distributions, dist.Name)
+	}
+	writeJSON(w, c)
+}
+
+// traced is a sort to replay: the input and the compares and swaps
+// made on it, each event being three numbers op, i and j.
+type traced struct {
+	Input     []int `json:"input"`
+	Events    []int `json:"events"`
+	Truncated bool  `json:"truncated"`
+}
+
+// serveTrace sorts the input of ?dist=, ?n= and ?seed= with
+// ?alg= and returns the trace, the same args give the same input.
+func serveTrace(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	i := slices.IndexFunc(trace.Algorithms, func(a trace.Algorithm) bool { return a.Name == q.Get("alg") })
+	if i < 0 {
+		http.Error(w, "unknown algorithm", http.StatusBadRequest)
+		return
+	}
+	alg := trace.Algorithms[i]
+	i = slices.IndexFunc(trace.Distributions, func(d trace.Distribution) bool { return d.Name == q.Get("dist") })
+	if i < 0 {
+		http.Error(w, "unknown distribution", http.StatusBadRequest)
+		return
+	}
+	dist := trace.Distributions[i]
+	n, err := strconv.Atoi(q.Get("n"))
+	if err != nil || n < 1 || n > maxLength {
+		http.Error(w, fmt.Sprintf("n must be in [1, %d]", maxLength), http.StatusBadRequest)
+		return
+	}
+	if alg.Valid != nil && !alg.Valid(n) {
+		http.Error(w, fmt.Sprintf("%s does not sort %d elements", alg.Name, n), http.StatusBadRequest)
+		return
+	}
+	seed, err := strconv.ParseInt(q.Get("seed"), 10, 64)
+	if err != nil {
+		http.Error(w, "bad seed", http.StatusBadRequest)
+		return
+	}
+
+	t := traced{Input: dist.Gen(rand.New(rand.NewSource(seed)), n)}
+	d := trace.NewRecorder(isort.IntSlice(slices.Clone(t.Input)))
+	alg.Sort(d)
+	events := d.Log()
+	if len(events) > maxEvents {
+		events, t.Truncated = events[:maxEvents], true
+	}
+	t.Events = make([]int, 0, 3*len(events))
+	for _, e := range events {
+		t.Events = append(t.Events, int(e.Op), e.I, e.J)
+	}
+	writeJSON(w, t)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Print(err)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/man-fish/goalgorithms/algorithms/sort/trace"
+)
+
+func get(t *testing.T, h http.Handler, url string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", url, nil))
+	return w
+}
+
+func TestPage(t *testing.T) {
+	w := get(t, newHandler(), "/")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "<title>sortviz</title>") {
+		t.Errorf("wanted the page but get %d %.100s", w.Code, w.Body.String())
+	}
+	// no external assets
+	if strings.Contains(w.Body.String(), "http://") || strings.Contains(w.Body.String(), "https://") {
+		t.Errorf("the page links an external asset")
+	}
+	if w := get(t, newHandler(), "/nope"); w.Code != http.StatusNotFound {
+		t.Errorf("wanted 404 but get %d", w.Code)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	h := newHandler()
+	var c choices
+	if err := json.Unmarshal(get(t, h, "/algorithms").Body.Bytes(), &c); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Algorithms) != len(trace.Algorithms) || len(c.Distributions) != len(trace.Distributions) {
+		t.Fatalf("wanted every algorithm and distribution but get %+v", c)
+	}
+	// a length every algorithm takes
+	for _, alg := range c.Algorithms {
+		w := get(t, h, "/trace?alg="+alg+"&dist=random&n=32&seed=7")
+		var tr traced
+		if err := json.Unmarshal(w.Body.Bytes(), &tr); err != nil {
+			t.Fatalf("%s: %v %s", alg, err, w.Body.String())
+		}
+		// replay the swaps
+		s := slices.Clone(tr.Input)
+		for i := 0; i < len(tr.Events); i += 3 {
+			if trace.Op(tr.Events[i]) == trace.Swap {
+				a, b := tr.Events[i+1], tr.Events[i+2]
+				s[a], s[b] = s[b], s[a]
+			}
+		}
+		if len(s) != 32 || !slices.IsSorted(s) || tr.Truncated {
+			t.Errorf("%s: replay get %v", alg, s)
+		}
+	}
+	// the same args give the same input
+	a, b := get(t, h, "/trace?alg=PdqSort&dist=random&n=20&seed=3"), get(t, h, "/trace?alg=HeapSort&dist=random&n=20&seed=3")
+	var ta, tb traced
+	json.Unmarshal(a.Body.Bytes(), &ta)
+	json.Unmarshal(b.Body.Bytes(), &tb)
+	if !slices.Equal(ta.Input, tb.Input) {
+		t.Errorf("wanted the same input but get %v and %v", ta.Input, tb.Input)
+	}
+}
+
+func TestTraceErrors(t *testing.T) {
+	for _, q := range []string{"alg=FooSort&dist=random&n=10&seed=1", "alg=PdqSort&dist=zipf&n=10&seed=1", "alg=PdqSort&dist=random&n=0&seed=1", "alg=PdqSort&dist=random&n=100000&seed=1", "alg=PdqSort&dist=random&n=10&seed=x", "alg=BitonicSort&dist=random&n=10&seed=1"} {
+		if w := get(t, newHandler(), "/trace?"+q); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: wanted 400 but get %d", q, w.Code)
+		}
+	}
+}
@@ -18,6 +18,8 @@ Doubly LinkedList on Wiki:
 */
 package doublylinkedlist
 
+import "github.com/man-fish/goalgorithms/datastructures/compare"
+
 // Element is an element of linked list
 type Element struct {
 	next, prev *Element
@@ -211,3 +213,66 @@ func (l *DoublyLinkedList) MoveToBack(e *Element) {
 	// see comment in List.Remove about initialization of l
 	l.move(e, l.root.prev)
 }
+
+// Sort sorts the list in increasing order with a bottom up merge sort, which
+// relinks the elements in place in O(nlog(n)) time and allocates nothing.
+// The values of the elements must be compare.Comparable, elements with
+// equal values keep their order.
+func (l *DoublyLinkedList) Sort() {
+	if l.len < 2 {
+		return
+	}
+	// sort the next links as a nil terminated list and then
+	// restore the prev links and the ring through the root
+	l.root.prev.next = nil
+	for size := 1; size < l.len; size *= 2 {
+		tail := &l.root
+		for p := l.root.next; p != nil; {
+			a := p
+			b := cut(a, size)
+			p = cut(b, size)
+			tail = mergeAfter(tail, a, b)
+		}
+	}
+	prev := &l.root
+	for e := l.root.next; e != nil; e = e.next {
+		e.prev = prev
+		prev = e
+	}
+	prev.next = &l.root
+	l.root.prev = prev
+}
+
+// cut ends the list from e after n elements and returns the rest
+func cut(e *Element, n int) *Element {
+	for ; e != nil && n > 1; n-- {
+		e = e.next
+	}
+	if e == nil {
+		return nil
+	}
+	rest := e.next
+	e.next = nil
+	return rest
+}
+
+// mergeAfter links the merge of the sorted lists a and b after tail and
+// returns its last element, the elements of a go before the equal ones of b.
+func mergeAfter(tail, a, b *Element) *Element {
+	for a != nil && b != nil {
+		if b.Value.(compare.Comparable).CompareTo(a.Value.(compare.Comparable)) < 0 {
+			tail.next, b = b, b.next
+		} else {
+			tail.next, a = a, a.next
+		}
+		tail = tail.next
+	}
+	if a == nil {
+		a = b
+	}
+	tail.next = a
+	for tail.next != nil {
+		tail = tail.next
+	}
+	return tail
+}
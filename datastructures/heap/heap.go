@@ -23,27 +23,36 @@ import (
 )
 
 // Heap is a binary tree implemented with an array
-type Heap struct {
-	tree []int
+type Heap[T any] struct {
+	tree []T
 	size int
-	// isMaxium determines whether the heap is a maximum heap or a minimum heap
-	isMaximum bool
+	// above reports whether a belongs above b, a > b for a maximum heap
+	above func(a, b T) bool
 }
 
 // New is the heap constructor
 // isMaximum determines the heap direction.
 // true for maximum, false for minimum.
-func New(tree []int, isMaximum bool) *Heap {
-	h := &Heap{
-		tree:      tree,
-		size:      len(tree),
-		isMaximum: isMaximum,
+func New(tree []int, isMaximum bool) *Heap[int] {
+	if isMaximum {
+		return NewFunc(tree, func(a, b int) bool { return a > b })
+	}
+	return NewFunc(tree, func(a, b int) bool { return a < b })
+}
+
+// NewFunc is the heap constructor for any element type,
+// the root of the heap is the element no other is above.
+func NewFunc[T any](tree []T, above func(a, b T) bool) *Heap[T] {
+	h := &Heap[T]{
+		tree:  tree,
+		size:  len(tree),
+		above: above,
 	}
 	h.buildHeap()
 	return h
 }
 
-func (h *Heap) buildHeap() {
+func (h *Heap[T]) buildHeap() {
 	// get the parent of the last leaf node
 	lst := h.size - 1
 	plst := (lst - 1) / 2
@@ -53,31 +62,31 @@ func (h *Heap) buildHeap() {
 	}
 }
 
-func (h *Heap) isHeapified() bool {
+func (h *Heap[T]) isHeapified() bool {
 	for i := 0; i < h.size; i++ {
 		l := 2*i + 1
 		r := 2*i + 2
-		if l < h.size && ((h.tree[l] < h.tree[i]) && true) != h.isMaximum {
+		if l < h.size && h.above(h.tree[l], h.tree[i]) {
 			return false
 		}
-		if r < h.size && ((h.tree[r] < h.tree[i]) && true) != h.isMaximum {
+		if r < h.size && h.above(h.tree[r], h.tree[i]) {
 			return false
 		}
 	}
 	return true
 }
 
-func (h *Heap) heapifyDown(i, n int) {
+func (h *Heap[T]) heapifyDown(i, n int) {
 	// get left and right node
 	l := 2*i + 1
 	r := 2*i + 2
 	max := i
 
 	// get the should swap one among l, r and i
-	if l < n && ((h.tree[l] < h.tree[max]) && true) != h.isMaximum {
+	if l < n && h.above(h.tree[l], h.tree[max]) {
 		max = l
 	}
-	if r < n && ((h.tree[r] < h.tree[max]) && true) != h.isMaximum {
+	if r < n && h.above(h.tree[r], h.tree[max]) {
 		max = r
 	}
 
@@ -88,10 +97,10 @@ func (h *Heap) heapifyDown(i, n int) {
 	}
 }
 
-func (h *Heap) heapifyUp(i int) {
+func (h *Heap[T]) heapifyUp(i int) {
 	// get parent
 	p := (i - 1) / 2
-	if p > 0 && ((h.tree[i] < h.tree[p]) && true) != h.isMaximum {
+	if i > 0 && h.above(h.tree[i], h.tree[p]) {
 		h.tree[p], h.tree[i] = h.tree[i], h.tree[p]
 		// heapify up
 		h.heapifyUp(p)
@@ -99,18 +108,51 @@ func (h *Heap) heapifyUp(i int) {
 }
 
 // Size returns size of heap
-func (h *Heap) Size() int {
+func (h *Heap[T]) Size() int {
 	return h.size
 }
 
 // Insert add node to heap and keep the heap heapified
-func (h *Heap) Insert(node int) {
-	h.tree = append(h.tree, node)
+func (h *Heap[T]) Insert(node T) {
+	h.tree = append(h.tree[:h.size], node)
 	h.size++
 	h.heapifyUp(h.size - 1)
 }
 
-func (h *Heap) String() string {
+// Peek returns the root of the heap, ok is false if the heap is empty
+func (h *Heap[T]) Peek() (root T, ok bool) {
+	if h.size == 0 {
+		return root, false
+	}
+	return h.tree[0], true
+}
+
+// Pop removes the root of the heap and returns it,
+// ok is false if the heap is empty
+func (h *Heap[T]) Pop() (root T, ok bool) {
+	if h.size == 0 {
+		return root, false
+	}
+	root = h.tree[0]
+	h.size--
+	h.tree[0] = h.tree[h.size]
+	h.tree = h.tree[:h.size]
+	h.heapifyDown(0, h.size)
+	return root, true
+}
+
+// Replace replaces the root of the heap with node and keeps the
+// heap heapified, it is a Pop followed by an Insert in one step.
+func (h *Heap[T]) Replace(node T) {
+	if h.size == 0 {
+		h.Insert(node)
+		return
+	}
+	h.tree[0] = node
+	h.heapifyDown(0, h.size)
+}
+
+func (h *Heap[T]) String() string {
 	heap := "\n"
 	idx := 0
 	for idx < h.size {
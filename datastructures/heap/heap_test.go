@@ -1,6 +1,7 @@
 package heap
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -23,3 +24,52 @@ func TestInsert(t *testing.T) {
 		t.Errorf("err size after insert:\n%v", h)
 	}
 }
+
+func TestInsertRoot(t *testing.T) {
+	// a new root must move all the way up
+	for _, c := range []struct {
+		max  bool
+		node int
+	}{{false, -10}, {true, 100}} {
+		h := New([]int{2, -3, 5, 4, 7, 1}, c.max)
+		h.Insert(c.node)
+		if root, _ := h.Peek(); root != c.node {
+			t.Errorf("maximum %v: wanted %d at the root but get %d", c.max, c.node, root)
+		}
+	}
+	// an insert after a pop keeps every element
+	h := New([]int{3, 1, 2}, false)
+	h.Pop()
+	h.Insert(0)
+	var got []int
+	for root, ok := h.Pop(); ok; root, ok = h.Pop() {
+		got = append(got, root)
+	}
+	if fmt.Sprint(got) != "[0 2 3]" {
+		t.Errorf("wanted [0 2 3] but get %v", got)
+	}
+}
+
+func TestPop(t *testing.T) {
+	h := NewFunc([]string{"pear", "fig", "apple", "kiwi"}, func(a, b string) bool { return a < b })
+	h.Insert("banana")
+	h.Replace("date")
+	var got []string
+	for {
+		root, ok := h.Pop()
+		if !ok {
+			break
+		}
+		if !h.isHeapified() {
+			t.Errorf("not a heap after pop:\n%v", h)
+		}
+		got = append(got, root)
+	}
+	want := "[banana date fig kiwi pear]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("wanted %s but get %v", want, got)
+	}
+	if _, ok := h.Peek(); ok {
+		t.Errorf("peek of an empty heap")
+	}
+}
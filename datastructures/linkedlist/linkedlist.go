@@ -140,3 +140,59 @@ func (l *LinkedList) Search(x compare.Comparable) *Element {
 	}
 	return nil
 }
+
+// Sort sorts the list in increasing order with a bottom up merge sort, which
+// relinks the elements in place in O(nlog(n)) time and allocates nothing.
+// The values of the elements must be compare.Comparable, elements with
+// equal values keep their order.
+func (l *LinkedList) Sort() {
+	for size := 1; ; size *= 2 {
+		// merge the pairs of sorted runs of size elements
+		tail := &l.root
+		p, merges := l.root.Next, 0
+		for p != nil {
+			a := p
+			b := cut(a, size)
+			p = cut(b, size)
+			tail = mergeAfter(tail, a, b)
+			merges++
+		}
+		if merges <= 1 {
+			return
+		}
+	}
+}
+
+// cut ends the list from e after n elements and returns the rest
+func cut(e *Element, n int) *Element {
+	for ; e != nil && n > 1; n-- {
+		e = e.Next
+	}
+	if e == nil {
+		return nil
+	}
+	rest := e.Next
+	e.Next = nil
+	return rest
+}
+
+// mergeAfter links the merge of the sorted lists a and b after tail and
+// returns its last element, the elements of a go before the equal ones of b.
+func mergeAfter(tail, a, b *Element) *Element {
+	for a != nil && b != nil {
+		if b.Value.(compare.Comparable).CompareTo(a.Value.(compare.Comparable)) < 0 {
+			tail.Next, b = b, b.Next
+		} else {
+			tail.Next, a = a, a.Next
+		}
+		tail = tail.Next
+	}
+	if a == nil {
+		a = b
+	}
+	tail.Next = a
+	for tail.Next != nil {
+		tail = tail.Next
+	}
+	return tail
+}
@@ -0,0 +1,42 @@
+package linkedlist
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/man-fish/goalgorithms/datastructures/compare"
+)
+
+// item is a key with its position in the input to check stability
+type item struct {
+	key, pos int
+}
+
+// Equal implements compare.Comparable
+func (a item) Equal(c compare.Comparable) bool { return a.CompareTo(c) == 0 }
+
+// CompareTo implements compare.Comparable, items compare by key only
+func (a item) CompareTo(c compare.Comparable) int { return cmp.Compare(a.key, c.(item).key) }
+
+func TestSort(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 100, 1001} {
+		l := New()
+		want := make([]item, n)
+		for i := range want {
+			want[i] = item{key: rand.Intn(n/4 + 1), pos: i}
+			l.InsertAtLast(want[i])
+		}
+		slices.SortStableFunc(want, func(a, b item) int { return a.CompareTo(b) })
+
+		l.Sort()
+		var got []item
+		for p := l.root.Next; p != nil; p = p.Next {
+			got = append(got, p.Value.(item))
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("sort of %d: wanted %v but get %v", n, want, got)
+		}
+	}
+}
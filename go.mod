@@ -1,3 +1,3 @@
 module github.com/man-fish/goalgorithms
 
-go 1.15
+go 1.23